@@ -1,10 +1,12 @@
 package wrappederror
 
 import (
+	"encoding/json"
 	"fmt"
 	"path"
 	"runtime"
-	"runtime/debug"
+	"strings"
+	"sync"
 )
 
 // Values to use when we can't get components of the caller.
@@ -14,67 +16,250 @@ const (
 	callerLineNumberUnknown   int    = 0
 )
 
+// The maximum number of stack frames captured by a caller.
+const callerMaxStackDepth = 64
+
+// The function that begins a panic.
+const callerPanicFunction = "runtime.gopanic"
+
 // Caller types contain call information.
+//
+// Callers only capture program counters when they are created. Their frames,
+// stack trace and source fragment are resolved the first time they are
+// accessed.
 type Caller struct {
 
 	// The caller's file.
-	File string `json:"file"`
+	File string
 
 	// The caller's function name.
-	Function string `json:"function"`
+	Function string
 
 	// The caller's line number.
-	Line int `json:"line"`
+	Line int
+
+	// The caller's lazily resolved stack.
+	stack *callerStack
+}
+
+// callerStack types resolve a caller's frames, stack trace and source fragment
+// on first access.
+type callerStack struct {
+
+	// The program counters of the goroutine that created the caller.
+	pcs []uintptr
+
+	// The number of leading helper frames to skip when resolving frames.
+	skipFrames int
 
-	// A stack trace of the goroutine that created the caller.
-	StackTrace string `json:"stackTrace"`
+	// The full path of the caller's file.
+	file string
 
-	// Fragment returns raw source code around the line that the caller was
-	// created on. This function will return an empty string if the process is not
-	// currently being debugged.
-	Fragment *SourceFragment `json:"sourceFragment"`
+	// Whether or not to capture the source fragment, and its radius.
+	captureFragment bool
+	fragmentRadius  int
+
+	frames     []Frame
+	framesOnce sync.Once
+	fragment   *SourceFragment
+	fragOnce   sync.Once
+	stackTrace string
+	traceOnce  sync.Once
 }
 
 // Initializers
 
 // currentCaller gets the current caller with the given skip.
 func newCaller(skip int, captureFragment bool, fragmentRadius int) *Caller {
-	st := debug.Stack()
+	var spcs [callerMaxStackDepth]uintptr
+	n := runtime.Callers(skip+1, spcs[:])
+	pcs := make([]uintptr, n)
+	copy(pcs, spcs[:n])
+	return newCallerWithPCs(pcs, captureFragment, fragmentRadius)
+}
+
+// newCallerWithPCs creates a caller from the program counters, pcs, and only
+// resolves the first frame that doesn't belong to a helper function.
+func newCallerWithPCs(
+	pcs []uintptr,
+	captureFragment bool,
+	fragmentRadius int,
+) *Caller {
+	if len(pcs) == 0 {
+		// *Wah, wah, wah* sound effect.
+		return &Caller{
+			File:     callerFileNameUnknown,
+			Function: callerFunctionNameUnknown,
+			Line:     callerLineNumberUnknown,
+		}
+	}
+
+	rfs := runtime.CallersFrames(pcs)
+	f, more := rfs.Next()
+	skipFrames := 0
+	for more && packageState.isHelper(f.Function) {
+		f, more = rfs.Next()
+		skipFrames++
+	}
+
+	return newCallerWithFrame(pcs, f, skipFrames, captureFragment, fragmentRadius)
+}
+
+// newPanicCaller gets the caller that panicked with the given skip. It must be
+// called by a deferred function while the goroutine is panicking.
+//
+// The caller's frames begin with the frame that called panic, or the frame
+// that caused a run-time panic. If the goroutine isn't panicking, then the
+// current caller is returned.
+func newPanicCaller(
+	skip int,
+	captureFragment bool,
+	fragmentRadius int,
+) *Caller {
+	var spcs [callerMaxStackDepth]uintptr
+	n := runtime.Callers(skip+1, spcs[:])
+	pcs := make([]uintptr, n)
+	copy(pcs, spcs[:n])
 
-	if pc, fp, ln, ok := runtime.Caller(skip); ok {
-		var sf *SourceFragment
-		if captureFragment {
-			sf, _ = newSourceFragment(fp, ln, fragmentRadius)
+	frames := newFrames(pcs)
+	for i, f := range frames {
+		if f.Function != callerPanicFunction {
+			continue
 		}
 
-		_, fin := path.Split(fp)
-		if f := runtime.FuncForPC(pc); f != nil {
-			return &Caller{
-				fin,
-				f.Name(),
-				ln,
-				string(st),
-				sf,
-			}
+		j := i + 1
+		for j < len(frames)-1 && strings.HasPrefix(frames[j].Function, "runtime.") {
+			j++
 		}
 
-		return &Caller{
-			fin,
-			callerFunctionNameUnknown,
-			ln,
-			string(st),
-			sf,
+		if j >= len(frames) {
+			break
+		}
+
+		rf := runtime.Frame{
+			Function: frames[j].Function,
+			File:     frames[j].File,
+			Line:     frames[j].Line,
 		}
+		return newCallerWithFrame(pcs, rf, j, captureFragment, fragmentRadius)
 	}
 
-	// *Wah, wah, wah* sound effect.
+	return newCallerWithPCs(pcs, captureFragment, fragmentRadius)
+}
+
+// newCallerWithFrame creates a caller from the program counters, pcs, whose
+// call information is the frame, f. The caller's frames begin after the first
+// skipFrames frames.
+func newCallerWithFrame(
+	pcs []uintptr,
+	f runtime.Frame,
+	skipFrames int,
+	captureFragment bool,
+	fragmentRadius int,
+) *Caller {
+	fn := f.Function
+	if fn == "" {
+		fn = callerFunctionNameUnknown
+	}
+
+	_, fin := path.Split(f.File)
 	return &Caller{
-		callerFileNameUnknown,
-		callerFunctionNameUnknown,
-		callerLineNumberUnknown,
-		string(st),
-		nil,
+		File:     fin,
+		Function: fn,
+		Line:     f.Line,
+		stack: &callerStack{
+			pcs:             pcs,
+			skipFrames:      skipFrames,
+			file:            f.File,
+			captureFragment: captureFragment,
+			fragmentRadius:  fragmentRadius,
+		},
+	}
+}
+
+// newResolvedCallerStack creates a caller stack that has already been resolved.
+func newResolvedCallerStack(
+	frames []Frame,
+	fragment *SourceFragment,
+	stackTrace string,
+) *callerStack {
+	s := new(callerStack)
+	s.framesOnce.Do(func() { s.frames = frames })
+	s.fragOnce.Do(func() { s.fragment = fragment })
+	if len(frames) == 0 {
+		s.traceOnce.Do(func() { s.stackTrace = stackTrace })
 	}
+	return s
+}
+
+// Exported methods
+
+// Frames returns the stack frames of the goroutine that created the caller
+// beginning with the caller's own frame.
+//
+// Frames are resolved from the caller's program counters on first access.
+func (c Caller) Frames() []Frame {
+	if c.stack == nil {
+		return nil
+	}
+
+	c.stack.framesOnce.Do(func() {
+		frames := newFrames(c.stack.pcs)
+		if c.stack.skipFrames < len(frames) {
+			c.stack.frames = frames[c.stack.skipFrames:]
+		}
+	})
+	return c.stack.frames
+}
+
+// StackTrace returns a stack trace of the goroutine that created the caller.
+//
+// The trace is rendered from the caller's frames on first access.
+func (c Caller) StackTrace() string {
+	if c.stack == nil {
+		return ""
+	}
+
+	c.stack.traceOnce.Do(func() {
+		var b strings.Builder
+		for _, f := range c.Frames() {
+			b.WriteString(f.String())
+			b.WriteString("\n")
+		}
+		c.stack.stackTrace = b.String()
+	})
+	return c.stack.stackTrace
+}
+
+// Fragment returns raw source code around the line that the caller was created
+// on, or nil if source fragments weren't captured or the source file couldn't
+// be read.
+//
+// The source file is read on first access, and matches of the package's
+// current redaction patterns are redacted from a copy of the fragment each
+// time it is returned.
+func (c Caller) Fragment() *SourceFragment {
+	if c.stack == nil {
+		return nil
+	}
+
+	c.stack.fragOnce.Do(func() {
+		if c.stack.captureFragment {
+			c.stack.fragment, _ = newSourceFragment(
+				c.stack.file,
+				c.Line,
+				c.stack.fragmentRadius,
+			)
+		}
+	})
+
+	if c.stack.fragment == nil {
+		return nil
+	}
+
+	f := *c.stack.fragment
+	f.Source = redactString(f.Source)
+	return &f
 }
 
 // Stringer interface methods
@@ -87,3 +272,30 @@ func (c Caller) String() string {
 		c.Line,
 	)
 }
+
+// JSON Marshaler interface methods
+
+// MarshalJSON marshals the caller in to JSON data.
+func (c Caller) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newJSONCaller(c, JSONFieldSetFull))
+}
+
+// UnmarshalJSON unmarshals the JSON data in to the caller.
+func (c *Caller) UnmarshalJSON(data []byte) error {
+	var jc jsonCaller
+	if err := json.Unmarshal(data, &jc); err != nil {
+		return err
+	}
+
+	*c = Caller{
+		File:     jc.File,
+		Function: jc.Function,
+		Line:     jc.Line,
+		stack: newResolvedCallerStack(
+			jc.Frames,
+			jc.Fragment,
+			jc.StackTrace,
+		),
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package wrappederror
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -37,7 +39,7 @@ func TestNewCallerFailure_1(t *testing.T) {
 	if c.File != callerFileNameUnknown ||
 		c.Function != callerFunctionNameUnknown ||
 		c.Line != callerLineNumberUnknown ||
-		c.Fragment != nil {
+		c.Fragment() != nil {
 		t.Errorf("Unknown caller information %s.\n", c)
 	}
 }
@@ -56,19 +58,102 @@ func TestCallerFunction(t *testing.T) {
 }
 
 func TestCallerLine(t *testing.T) {
-	if testCallers.c0.Line != 14 {
+	if testCallers.c0.Line != 16 {
 		t.Errorf("Incorrect line number: %d\n", testCallers.c0.Line)
 	}
 }
 
 func TestCallerStack(t *testing.T) {
-	if testCallers.c0.StackTrace == "" {
+	if testCallers.c0.StackTrace() == "" {
 		t.Error("Expected a stack trace.")
 	}
 }
 
+func TestCallerFrames(t *testing.T) {
+	fs := testCallers.c0.Frames()
+	if len(fs) == 0 {
+		t.Fatal("Expected frames.")
+	}
+
+	f := fs[0]
+	if f.Function != testCallers.c0.Function {
+		t.Errorf("Unexpected function %s.\n", f.Function)
+	}
+	if f.Package != "github.com/colinc86/wrappederror" {
+		t.Errorf("Unexpected package %s.\n", f.Package)
+	}
+	if !strings.HasSuffix(f.File, "/caller_test.go") {
+		t.Errorf("Expected a full file path but received %s.\n", f.File)
+	}
+	if f.Line != testCallers.c0.Line {
+		t.Errorf("Unexpected line %d.\n", f.Line)
+	}
+	if f.PC == 0 {
+		t.Error("Expected a program counter.")
+	}
+}
+
+func TestCallerFramesSkipPackageFrames(t *testing.T) {
+	e := New(nil, "test")
+	for _, f := range e.Caller.Frames() {
+		if f.Package == "github.com/colinc86/wrappederror" &&
+			!strings.HasSuffix(f.File, "_test.go") {
+			t.Errorf("Unexpected package frame %s.\n", f.Function)
+		}
+	}
+}
+
+func TestCallerLazyResolution(t *testing.T) {
+	c := newCaller(1, true, 2)
+	if c.stack.frames != nil || c.stack.fragment != nil {
+		t.Fatal("Expected an unresolved caller.")
+	}
+
+	if c.Fragment() == nil {
+		t.Error("Expected a source fragment.")
+	}
+	if c.stack.frames != nil {
+		t.Error("Expected unresolved frames.")
+	}
+	if c.StackTrace() == "" {
+		t.Error("Expected a stack trace.")
+	}
+	if c.stack.frames == nil {
+		t.Error("Expected resolved frames.")
+	}
+}
+
+func TestCallerZeroValue(t *testing.T) {
+	var c Caller
+	if c.Frames() != nil || c.StackTrace() != "" || c.Fragment() != nil {
+		t.Error("Expected an empty caller.")
+	}
+}
+
+func TestCallerJSON(t *testing.T) {
+	data, err := json.Marshal(testCallers.c0)
+	if err != nil {
+		t.Fatalf("Error marshaling json: %s\n", err)
+	}
+
+	var c Caller
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatalf("Error unmarshaling json: %s\n", err)
+	}
+
+	if c.String() != testCallers.c0.String() {
+		t.Errorf("Expected %s but received %s.\n", testCallers.c0, c)
+	}
+	if len(c.Frames()) != len(testCallers.c0.Frames()) {
+		t.Errorf("Expected %d frames but received %d.\n", len(testCallers.c0.Frames()), len(c.Frames()))
+	}
+	if c.StackTrace() != testCallers.c0.StackTrace() {
+		t.Error("Unexpected stack trace.")
+	}
+}
+
 func TestCallerSource(t *testing.T) {
-	if testCallers.c2.Fragment.Source == "" {
+	if testCallers.c2.Fragment().Source == "" {
 		t.Error("Expected a source trace.")
 	}
 }
@@ -0,0 +1,136 @@
+package wrappederror
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+)
+
+// CBOR major types.
+const (
+	cborMajorUnsigned byte = 0
+	cborMajorNegative byte = 1
+	cborMajorText     byte = 3
+	cborMajorArray    byte = 4
+	cborMajorMap      byte = 5
+)
+
+// CBOR simple values.
+const (
+	cborFalse   byte = 0xf4
+	cborTrue    byte = 0xf5
+	cborNull    byte = 0xf6
+	cborFloat64 byte = 0xfb
+)
+
+// CBOREncoder types encode errors as Concise Binary Object Representation
+// (RFC 8949) data for compact transport.
+//
+// The encoded data has the same structure as the error's full JSON
+// representation. Integers are encoded as CBOR integers and all other numbers
+// as double-precision floats.
+type CBOREncoder struct{}
+
+// Encode encodes the error, e, and writes the result to w.
+func (enc CBOREncoder) Encode(w io.Writer, e Error) error {
+	n, err := newErrorEncoderNode(e)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	enc.writeNode(&b, n)
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// Non-exported methods
+
+// writeNode writes the node, n.
+func (enc CBOREncoder) writeNode(b *bytes.Buffer, n *encoderNode) {
+	switch n.kind {
+	case encoderNodeKindNull:
+		b.WriteByte(cborNull)
+	case encoderNodeKindBool:
+		if n.value.(bool) {
+			b.WriteByte(cborTrue)
+		} else {
+			b.WriteByte(cborFalse)
+		}
+	case encoderNodeKindNumber:
+		enc.writeNumber(b, n.value.(json.Number))
+	case encoderNodeKindString:
+		enc.writeText(b, n.value.(string))
+	case encoderNodeKindArray:
+		enc.writeHead(b, cborMajorArray, uint64(len(n.items)))
+		for _, item := range n.items {
+			enc.writeNode(b, item)
+		}
+	case encoderNodeKindObject:
+		enc.writeHead(b, cborMajorMap, uint64(len(n.fields)))
+		for _, f := range n.fields {
+			enc.writeText(b, f.key)
+			enc.writeNode(b, f.node)
+		}
+	}
+}
+
+// writeNumber writes the number, v, as an integer if possible, and a float
+// otherwise.
+func (enc CBOREncoder) writeNumber(b *bytes.Buffer, v json.Number) {
+	if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+		if i >= 0 {
+			enc.writeHead(b, cborMajorUnsigned, uint64(i))
+		} else {
+			enc.writeHead(b, cborMajorNegative, uint64(-1-i))
+		}
+		return
+	}
+
+	if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+		enc.writeHead(b, cborMajorUnsigned, u)
+		return
+	}
+
+	f, _ := v.Float64()
+	var fb [8]byte
+	binary.BigEndian.PutUint64(fb[:], math.Float64bits(f))
+	b.WriteByte(cborFloat64)
+	b.Write(fb[:])
+}
+
+// writeText writes the text string, s.
+func (enc CBOREncoder) writeText(b *bytes.Buffer, s string) {
+	enc.writeHead(b, cborMajorText, uint64(len(s)))
+	b.WriteString(s)
+}
+
+// writeHead writes a data item head with the major type, m, and argument, v.
+func (enc CBOREncoder) writeHead(b *bytes.Buffer, m byte, v uint64) {
+	m <<= 5
+	switch {
+	case v < 24:
+		b.WriteByte(m | byte(v))
+	case v <= math.MaxUint8:
+		b.WriteByte(m | 24)
+		b.WriteByte(byte(v))
+	case v <= math.MaxUint16:
+		var a [2]byte
+		binary.BigEndian.PutUint16(a[:], uint16(v))
+		b.WriteByte(m | 25)
+		b.Write(a[:])
+	case v <= math.MaxUint32:
+		var a [4]byte
+		binary.BigEndian.PutUint32(a[:], uint32(v))
+		b.WriteByte(m | 26)
+		b.Write(a[:])
+	default:
+		var a [8]byte
+		binary.BigEndian.PutUint64(a[:], v)
+		b.WriteByte(m | 27)
+		b.Write(a[:])
+	}
+}
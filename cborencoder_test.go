@@ -0,0 +1,128 @@
+package wrappederror
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestCBOREncoder(t *testing.T) {
+	e := testEncoderError()
+
+	var b bytes.Buffer
+	if err := (CBOREncoder{}).Encode(&b, *e); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	v, rest := testDecodeCBOR(t, b.Bytes())
+	if len(rest) != 0 {
+		t.Errorf("Unexpected trailing data of length %d.\n", len(rest))
+	}
+
+	data, _ := json.Marshal(newJSONWErrorFull(*e, JSONFieldSetMinimal))
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var ex interface{}
+	if err := d.Decode(&ex); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	if !reflect.DeepEqual(v, ex) {
+		t.Errorf("Expected %+v but received %+v.\n", ex, v)
+	}
+}
+
+func TestCBOREncoderNumbers(t *testing.T) {
+	t.Run("CBOR number 0", func(t *testing.T) { testCBOREncoderNumber(t, "0") })
+	t.Run("CBOR number 1", func(t *testing.T) { testCBOREncoderNumber(t, "23") })
+	t.Run("CBOR number 2", func(t *testing.T) { testCBOREncoderNumber(t, "24") })
+	t.Run("CBOR number 3", func(t *testing.T) { testCBOREncoderNumber(t, "300") })
+	t.Run("CBOR number 4", func(t *testing.T) { testCBOREncoderNumber(t, "70000") })
+	t.Run("CBOR number 5", func(t *testing.T) { testCBOREncoderNumber(t, "5000000000") })
+	t.Run("CBOR number 6", func(t *testing.T) { testCBOREncoderNumber(t, "-1") })
+	t.Run("CBOR number 7", func(t *testing.T) { testCBOREncoderNumber(t, "-500") })
+	t.Run("CBOR number 8", func(t *testing.T) {
+		testCBOREncoderNumber(t, "18446744073709551615")
+	})
+	t.Run("CBOR number 9", func(t *testing.T) { testCBOREncoderNumber(t, "1.5") })
+}
+
+func testCBOREncoderNumber(t *testing.T, n string) {
+	var b bytes.Buffer
+	(CBOREncoder{}).writeNumber(&b, json.Number(n))
+	v, _ := testDecodeCBOR(t, b.Bytes())
+	if v.(json.Number).String() != n {
+		t.Errorf("Expected %s but received %s.\n", n, v)
+	}
+}
+
+// testDecodeCBOR decodes the subset of CBOR written by CBOREncoder. Numbers are
+// returned as json.Number values.
+func testDecodeCBOR(t *testing.T, data []byte) (interface{}, []byte) {
+	if len(data) == 0 {
+		t.Fatal("Unexpected end of data.")
+	}
+
+	switch data[0] {
+	case cborNull:
+		return nil, data[1:]
+	case cborTrue:
+		return true, data[1:]
+	case cborFalse:
+		return false, data[1:]
+	case cborFloat64:
+		f := math.Float64frombits(binary.BigEndian.Uint64(data[1:9]))
+		return json.Number(strconv.FormatFloat(f, 'f', -1, 64)), data[9:]
+	}
+
+	m := data[0] >> 5
+	a := data[0] & 0x1f
+	data = data[1:]
+
+	var v uint64
+	switch {
+	case a < 24:
+		v = uint64(a)
+	case a == 24:
+		v, data = uint64(data[0]), data[1:]
+	case a == 25:
+		v, data = uint64(binary.BigEndian.Uint16(data)), data[2:]
+	case a == 26:
+		v, data = uint64(binary.BigEndian.Uint32(data)), data[4:]
+	default:
+		v, data = binary.BigEndian.Uint64(data), data[8:]
+	}
+
+	switch m {
+	case cborMajorUnsigned:
+		return json.Number(strconv.FormatUint(v, 10)), data
+	case cborMajorNegative:
+		return json.Number(strconv.FormatInt(-1-int64(v), 10)), data
+	case cborMajorText:
+		return string(data[:v]), data[v:]
+	case cborMajorArray:
+		items := make([]interface{}, 0, v)
+		for i := uint64(0); i < v; i++ {
+			var item interface{}
+			item, data = testDecodeCBOR(t, data)
+			items = append(items, item)
+		}
+		return items, data
+	case cborMajorMap:
+		fields := make(map[string]interface{}, v)
+		for i := uint64(0); i < v; i++ {
+			var k, item interface{}
+			k, data = testDecodeCBOR(t, data)
+			item, data = testDecodeCBOR(t, data)
+			fields[k.(string)] = item
+		}
+		return fields, data
+	}
+
+	t.Fatalf("Unexpected major type %d.\n", m)
+	return nil, nil
+}
@@ -1,27 +1,51 @@
 package wrappederror
 
+import (
+	"regexp"
+	"time"
+)
+
 // Default configuration values.
 const (
 	configDefaultCaptureCaller          = true
 	configDefaultCaptureProcess         = true
+	configDefaultCaptureMemory          = false
 	configDefaultMarshalMinimalJSON     = true
+	configDefaultJSONFieldSet           = JSONFieldSetFull
 	configDefaultCaptureSourceFragments = true
 	configDefaultIgnoreBreakpoints      = true
 	configDefaultTrackSimilarErrors     = true
 	configDefaultSourceFragmentRadius   = 2
 	configDefaultNextErrorIndex         = 1
+	configDefaultRedactionMask          = "[REDACTED]"
+	configDefaultSimilarErrorCapacity   = 4096
+	configDefaultSimilarErrorTTL        = time.Duration(0)
+	configDefaultSimilarErrorWindow     = time.Duration(0)
+	configDefaultSampleLimit            = 0
+	configDefaultReporterQueueSize      = 256
 )
 
 // Configuration types keep track of the package's configuration.
 type Configuration struct {
 	captureCaller          *safeValue
 	captureProcess         *safeValue
+	captureMemory          *safeValue
 	marshalMinimalJSON     *safeValue
+	jsonFieldSet           *safeValue
 	captureSourceFragments *safeValue
 	sourceFragmentRadius   *safeValue
 	ignoreBreakpoints      *safeValue
 	nextErrorIndex         *safeValue
 	trackSimilarErrors     *safeValue
+	redactionPatterns      *safeValue
+	redactionMask          *safeValue
+	fingerprinter          *safeValue
+	similarErrorCapacity   *safeValue
+	similarErrorTTL        *safeValue
+	similarErrorWindow     *safeValue
+	sampleLimit            *safeValue
+	reporterQueueSize      *safeValue
+	contextExtractors      *safeValue
 }
 
 // Initializers
@@ -31,12 +55,23 @@ func newConfiguration() *Configuration {
 	return &Configuration{
 		captureCaller:          newSafeValue(configDefaultCaptureCaller),
 		captureProcess:         newSafeValue(configDefaultCaptureProcess),
+		captureMemory:          newSafeValue(configDefaultCaptureMemory),
 		marshalMinimalJSON:     newSafeValue(configDefaultMarshalMinimalJSON),
+		jsonFieldSet:           newSafeValue(configDefaultJSONFieldSet),
 		captureSourceFragments: newSafeValue(configDefaultCaptureSourceFragments),
 		sourceFragmentRadius:   newSafeValue(configDefaultSourceFragmentRadius),
 		ignoreBreakpoints:      newSafeValue(configDefaultIgnoreBreakpoints),
 		nextErrorIndex:         newSafeValue(configDefaultNextErrorIndex),
 		trackSimilarErrors:     newSafeValue(configDefaultTrackSimilarErrors),
+		redactionPatterns:      newSafeValue([]*regexp.Regexp(nil)),
+		redactionMask:          newSafeValue(configDefaultRedactionMask),
+		fingerprinter:          newSafeValue(Fingerprinter(MessageFingerprinter{})),
+		similarErrorCapacity:   newSafeValue(configDefaultSimilarErrorCapacity),
+		similarErrorTTL:        newSafeValue(configDefaultSimilarErrorTTL),
+		similarErrorWindow:     newSafeValue(configDefaultSimilarErrorWindow),
+		sampleLimit:            newSafeValue(configDefaultSampleLimit),
+		reporterQueueSize:      newSafeValue(configDefaultReporterQueueSize),
+		contextExtractors:      newSafeValue(make(map[string]ContextExtractor)),
 	}
 }
 
@@ -105,6 +140,22 @@ func (c *Configuration) MarshalMinimalJSON() bool {
 	return c.marshalMinimalJSON.get().(bool)
 }
 
+// SetJSONFieldSet sets the fields that are included when errors are marshaled
+// in to full JSON objects.
+//
+// Setting the field set also sets the marshal minimal JSON flag to false so
+// that errors are marshaled in to full JSON objects with the given fields.
+func (c *Configuration) SetJSONFieldSet(fields JSONFieldSet) {
+	c.jsonFieldSet.set(fields)
+	c.SetMarshalMinimalJSON(false)
+}
+
+// JSONFieldSet gets the fields that are included when errors are marshaled in
+// to full JSON objects.
+func (c *Configuration) JSONFieldSet() JSONFieldSet {
+	return c.jsonFieldSet.get().(JSONFieldSet)
+}
+
 // Caller interface values
 
 // CaptureSourceFragments gets a boolean that indicates whether or not new
@@ -133,6 +184,21 @@ func (c *Configuration) SourceFragmentRadius() int {
 
 // Process interface values
 
+// SetCaptureMemory sets a flag to determine if new errors capture memory
+// statistics with their process information.
+//
+// Memory statistics aren't captured by default because reading them stops the
+// world. Only set this to true if errors aren't created on hot paths.
+func (c *Configuration) SetCaptureMemory(capture bool) {
+	c.captureMemory.set(capture)
+}
+
+// CaptureMemory gets a boolean that indicates whether or not new errors capture
+// memory statistics with their process information.
+func (c *Configuration) CaptureMemory() bool {
+	return c.captureMemory.get().(bool)
+}
+
 // SetIgnoreBreakpoints tells all calls to `Break` on `Process` types to either
 // handle or ignore invocations.
 func (c *Configuration) SetIgnoreBreakpoints(ignore bool) {
@@ -169,6 +235,188 @@ func (c *Configuration) TrackSimilarErrors() bool {
 	return c.trackSimilarErrors.get().(bool)
 }
 
+// SetFingerprinter sets the fingerprinter that groups similar errors. If f is
+// nil, then the default MessageFingerprinter is used.
+func (c *Configuration) SetFingerprinter(f Fingerprinter) {
+	if f == nil {
+		f = MessageFingerprinter{}
+	}
+	c.fingerprinter.set(f)
+}
+
+// Fingerprinter gets the fingerprinter that groups similar errors.
+func (c *Configuration) Fingerprinter() Fingerprinter {
+	return c.fingerprinter.get().(Fingerprinter)
+}
+
+// SetSimilarErrorCapacity sets the maximum number of fingerprints that are
+// tracked for similar errors. When the capacity is reached, the least recently
+// seen fingerprint is forgotten. If capacity is less than 1, then the number of
+// fingerprints isn't limited.
+func (c *Configuration) SetSimilarErrorCapacity(capacity int) {
+	c.similarErrorCapacity.set(capacity)
+}
+
+// SimilarErrorCapacity gets the maximum number of fingerprints that are tracked
+// for similar errors.
+func (c *Configuration) SimilarErrorCapacity() int {
+	return c.similarErrorCapacity.get().(int)
+}
+
+// SetSimilarErrorTTL sets the duration after a fingerprint is last seen that
+// it's forgotten. If ttl is 0, then fingerprints are only forgotten when the
+// capacity is reached.
+func (c *Configuration) SetSimilarErrorTTL(ttl time.Duration) {
+	c.similarErrorTTL.set(ttl)
+}
+
+// SimilarErrorTTL gets the duration after a fingerprint is last seen that it's
+// forgotten.
+func (c *Configuration) SimilarErrorTTL() time.Duration {
+	return c.similarErrorTTL.get().(time.Duration)
+}
+
+// SetSimilarErrorWindow sets the duration of the sliding window that similar
+// errors are counted in. For example, with a window of 5 minutes, an error's
+// Similar metadata value is the number of similar errors created in the last
+// 5 minutes.
+//
+// If window is 0, then similar errors are counted since their fingerprint was
+// first seen.
+func (c *Configuration) SetSimilarErrorWindow(window time.Duration) {
+	c.similarErrorWindow.set(window)
+}
+
+// SimilarErrorWindow gets the duration of the sliding window that similar
+// errors are counted in.
+func (c *Configuration) SimilarErrorWindow() time.Duration {
+	return c.similarErrorWindow.get().(time.Duration)
+}
+
+// SetSampleLimit sets the number of similar errors that capture their call and
+// process information. Once the limit is reached, new similar errors only
+// capture metadata and are marked as sampled.
+//
+// Similar errors are counted in the similar error window, so errors are
+// captured in full again once the window passes. If limit is less than 1, or
+// similar errors aren't tracked, then errors aren't sampled.
+//
+// Sampled errors don't capture their call information unless they are
+// fingerprinted by a CallerFingerprinter, which needs it to find similar
+// errors.
+func (c *Configuration) SetSampleLimit(limit int) {
+	c.sampleLimit.set(limit)
+}
+
+// SampleLimit gets the number of similar errors that capture their call and
+// process information.
+func (c *Configuration) SampleLimit() int {
+	return c.sampleLimit.get().(int)
+}
+
+// SetReporterQueueSize sets the number of errors that can be queued for each
+// reporter before new errors are dropped.
+//
+// The size is used by reporters when they're registered, so it doesn't affect
+// reporters that have already been registered.
+func (c *Configuration) SetReporterQueueSize(size int) {
+	c.reporterQueueSize.set(size)
+}
+
+// ReporterQueueSize gets the number of errors that can be queued for each
+// reporter before new errors are dropped.
+func (c *Configuration) ReporterQueueSize() int {
+	return c.reporterQueueSize.get().(int)
+}
+
+// Context values
+
+// AddContextExtractor adds an extractor that extracts the field with the given
+// key from the contexts that errors are created with. If an extractor has
+// already been added with the key, then it's replaced.
+//
+// Use the ContextValueExtractor function to extract values stored in contexts
+// with context.WithValue.
+func (c *Configuration) AddContextExtractor(key string, x ContextExtractor) {
+	if x == nil {
+		return
+	}
+
+	c.contextExtractors.transform(func(v interface{}) interface{} {
+		m := copyContextExtractors(v.(map[string]ContextExtractor))
+		m[key] = x
+		return m
+	})
+}
+
+// RemoveContextExtractor removes the extractor with the given key.
+func (c *Configuration) RemoveContextExtractor(key string) {
+	c.contextExtractors.transform(func(v interface{}) interface{} {
+		m := copyContextExtractors(v.(map[string]ContextExtractor))
+		delete(m, key)
+		return m
+	})
+}
+
+// ContextExtractors gets the extractors that extract fields from the contexts
+// that errors are created with, keyed by their field's key.
+func (c *Configuration) ContextExtractors() map[string]ContextExtractor {
+	return copyContextExtractors(
+		c.contextExtractors.get().(map[string]ContextExtractor),
+	)
+}
+
+// Redaction values
+
+// SetRedactionPatterns sets the patterns that are redacted from the strings
+// that errors output.
+//
+// Every match of a pattern is replaced with the redaction mask. If a pattern
+// contains subexpressions, then only the text matched by its subexpressions is
+// replaced.
+func (c *Configuration) SetRedactionPatterns(patterns ...*regexp.Regexp) {
+	p := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern != nil {
+			p = append(p, pattern)
+		}
+	}
+	c.redactionPatterns.set(p)
+}
+
+// AddRedactionPattern adds a pattern to the patterns that are redacted from the
+// strings that errors output.
+func (c *Configuration) AddRedactionPattern(pattern *regexp.Regexp) {
+	if pattern == nil {
+		return
+	}
+
+	c.redactionPatterns.transform(func(v interface{}) interface{} {
+		p := v.([]*regexp.Regexp)
+		np := make([]*regexp.Regexp, len(p), len(p)+1)
+		copy(np, p)
+		return append(np, pattern)
+	})
+}
+
+// RedactionPatterns gets the patterns that are redacted from the strings that
+// errors output.
+func (c *Configuration) RedactionPatterns() []*regexp.Regexp {
+	p := c.redactionPatterns.get().([]*regexp.Regexp)
+	return append([]*regexp.Regexp(nil), p...)
+}
+
+// SetRedactionMask sets the string that replaces redacted values. The default
+// mask is "[REDACTED]".
+func (c *Configuration) SetRedactionMask(mask string) {
+	c.redactionMask.set(mask)
+}
+
+// RedactionMask gets the string that replaces redacted values.
+func (c *Configuration) RedactionMask() string {
+	return c.redactionMask.get().(string)
+}
+
 // Non-exported methods
 
 // getAndIncrementNextErrorIndex gets the next error index and increments the
@@ -180,3 +428,35 @@ func (c *Configuration) getAndIncrementNextErrorIndex() int {
 	c.nextErrorIndex.mutex.Unlock()
 	return v
 }
+
+// similarErrorPolicy gets the policy that the package's error map tracks
+// similar errors with.
+func (c *Configuration) similarErrorPolicy() errorMapPolicy {
+	return errorMapPolicy{
+		capacity: c.SimilarErrorCapacity(),
+		ttl:      c.SimilarErrorTTL(),
+		window:   c.SimilarErrorWindow(),
+	}
+}
+
+// logFieldSet gets the fields that are included when errors are logged. If
+// errors are marshaled in to minimal JSON, then this is the minimal field set.
+func (c *Configuration) logFieldSet() JSONFieldSet {
+	if c.MarshalMinimalJSON() {
+		return JSONFieldSetMinimal
+	}
+	return c.JSONFieldSet()
+}
+
+// Non-exported functions
+
+// copyContextExtractors returns a copy of the extractors, m.
+func copyContextExtractors(
+	m map[string]ContextExtractor,
+) map[string]ContextExtractor {
+	c := make(map[string]ContextExtractor, len(m))
+	for k, x := range m {
+		c[k] = x
+	}
+	return c
+}
@@ -1,6 +1,10 @@
 package wrappederror
 
-import "testing"
+import (
+	"regexp"
+	"testing"
+	"time"
+)
 
 var testConfigurations struct {
 	c0 *Configuration
@@ -21,6 +25,9 @@ func TestNewConfiguration(t *testing.T) {
 	t.Run("Capture process", func(t *testing.T) {
 		testConfigurationValue(t, c.captureProcess, true)
 	})
+	t.Run("Capture memory", func(t *testing.T) {
+		testConfigurationValue(t, c.captureMemory, false)
+	})
 	t.Run("Capture source fragments", func(t *testing.T) {
 		testConfigurationValue(t, c.captureSourceFragments, true)
 	})
@@ -39,6 +46,37 @@ func TestNewConfiguration(t *testing.T) {
 	t.Run("Marshal minimal JSON", func(t *testing.T) {
 		testConfigurationValue(t, c.marshalMinimalJSON, true)
 	})
+	t.Run("JSON field set", func(t *testing.T) {
+		testConfigurationValue(t, c.jsonFieldSet, JSONFieldSetFull)
+	})
+	t.Run("Similar error capacity", func(t *testing.T) {
+		testConfigurationValue(t, c.similarErrorCapacity, 4096)
+	})
+	t.Run("Similar error TTL", func(t *testing.T) {
+		testConfigurationValue(t, c.similarErrorTTL, time.Duration(0))
+	})
+	t.Run("Similar error window", func(t *testing.T) {
+		testConfigurationValue(t, c.similarErrorWindow, time.Duration(0))
+	})
+	t.Run("Sample limit", func(t *testing.T) {
+		testConfigurationValue(t, c.sampleLimit, 0)
+	})
+	t.Run("Reporter queue size", func(t *testing.T) {
+		testConfigurationValue(t, c.reporterQueueSize, 256)
+	})
+	t.Run("Redaction mask", func(t *testing.T) {
+		testConfigurationValue(t, c.redactionMask, "[REDACTED]")
+	})
+	t.Run("Context extractors", func(t *testing.T) {
+		if len(c.ContextExtractors()) != 0 {
+			t.Errorf("Expected 0 but received %d.\n", len(c.ContextExtractors()))
+		}
+	})
+	t.Run("Redaction patterns", func(t *testing.T) {
+		if len(c.RedactionPatterns()) != 0 {
+			t.Errorf("Expected 0 but received %d.\n", len(c.RedactionPatterns()))
+		}
+	})
 }
 
 func TestConfigurationSet(t *testing.T) {
@@ -70,6 +108,17 @@ func TestConfigurationSet(t *testing.T) {
 	})
 }
 
+func TestConfigurationSetJSONFieldSet(t *testing.T) {
+	c := newConfiguration()
+	c.SetJSONFieldSet(JSONFieldCaller)
+	t.Run("JSON field set", func(t *testing.T) {
+		testConfigurationValue(t, c.jsonFieldSet, JSONFieldCaller)
+	})
+	t.Run("Marshal minimal JSON", func(t *testing.T) {
+		testConfigurationValue(t, c.marshalMinimalJSON, false)
+	})
+}
+
 func testConfigurationValue(t *testing.T, v *safeValue, ev interface{}) {
 	if v.get() != ev {
 		t.Errorf("Expected %v but received %v.\n", ev, v.get())
@@ -99,3 +148,79 @@ func testConfigurationNextErrorIndex(t *testing.T, c *Configuration, i int) {
 		t.Errorf("Expected %d but recived %+v.\n", i, c.NextErrorIndex())
 	}
 }
+
+func TestConfigurationRedactionPatterns(t *testing.T) {
+	c := newConfiguration()
+	p0 := regexp.MustCompile("a")
+	p1 := regexp.MustCompile("b")
+
+	c.SetRedactionPatterns(p0, nil)
+	c.AddRedactionPattern(p1)
+	c.AddRedactionPattern(nil)
+
+	p := c.RedactionPatterns()
+	if len(p) != 2 || p[0] != p0 || p[1] != p1 {
+		t.Fatalf("Unexpected patterns %v.\n", p)
+	}
+
+	p[0] = nil
+	if c.RedactionPatterns()[0] != p0 {
+		t.Error("Expected the patterns to be copied.")
+	}
+
+	c.SetRedactionPatterns()
+	if len(c.RedactionPatterns()) != 0 {
+		t.Errorf("Expected 0 but received %d.\n", len(c.RedactionPatterns()))
+	}
+}
+
+func TestConfigurationContextExtractors(t *testing.T) {
+	c := newConfiguration()
+	c.AddContextExtractor("a", DeadlineExtractor)
+	c.AddContextExtractor("b", ContextValueExtractor("b"))
+	c.AddContextExtractor("c", nil)
+
+	x := c.ContextExtractors()
+	if len(x) != 2 || x["a"] == nil || x["b"] == nil {
+		t.Fatalf("Unexpected extractors %v.\n", x)
+	}
+
+	delete(x, "a")
+	if len(c.ContextExtractors()) != 2 {
+		t.Error("Expected the extractors to be copied.")
+	}
+
+	c.RemoveContextExtractor("a")
+	if x := c.ContextExtractors(); len(x) != 1 || x["b"] == nil {
+		t.Errorf("Unexpected extractors %v.\n", x)
+	}
+}
+
+func TestConfigurationFingerprinter(t *testing.T) {
+	c := newConfiguration()
+	if _, ok := c.Fingerprinter().(MessageFingerprinter); !ok {
+		t.Errorf("Expected MessageFingerprinter but received %T.\n", c.Fingerprinter())
+	}
+
+	c.SetFingerprinter(TypeFingerprinter{})
+	if _, ok := c.Fingerprinter().(TypeFingerprinter); !ok {
+		t.Errorf("Expected TypeFingerprinter but received %T.\n", c.Fingerprinter())
+	}
+
+	c.SetFingerprinter(nil)
+	if _, ok := c.Fingerprinter().(MessageFingerprinter); !ok {
+		t.Errorf("Expected MessageFingerprinter but received %T.\n", c.Fingerprinter())
+	}
+}
+
+func TestConfigurationSimilarErrorPolicy(t *testing.T) {
+	c := newConfiguration()
+	c.SetSimilarErrorCapacity(10)
+	c.SetSimilarErrorTTL(time.Hour)
+	c.SetSimilarErrorWindow(time.Minute)
+
+	p := c.similarErrorPolicy()
+	if p.capacity != 10 || p.ttl != time.Hour || p.window != time.Minute {
+		t.Errorf("Unexpected policy %+v.\n", p)
+	}
+}
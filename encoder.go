@@ -0,0 +1,71 @@
+package wrappederror
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// ErrEncoderAlreadyRegistered indicates that an encoder has already been
+// registered with a given name.
+var ErrEncoderAlreadyRegistered = errors.New("encoder already registered")
+
+// ErrEncoderNotFound indicates that no encoder has been registered with a given
+// name.
+var ErrEncoderNotFound = errors.New("encoder not found")
+
+// The names of the package's built-in encoders.
+const (
+	EncoderNameJSON   = "json"
+	EncoderNameLogfmt = "logfmt"
+	EncoderNameText   = "text"
+	EncoderNameCBOR   = "cbor"
+)
+
+// Encoder types encode errors in to a particular format and write the encoded
+// data to a writer.
+type Encoder interface {
+
+	// Encode encodes the error, e, and writes the result to w.
+	Encode(w io.Writer, e Error) error
+}
+
+// EncoderFunc types are functions that implement the Encoder interface.
+type EncoderFunc func(w io.Writer, e Error) error
+
+// Encode calls f(w, e).
+func (f EncoderFunc) Encode(w io.Writer, e Error) error {
+	return f(w, e)
+}
+
+// JSONEncoder types encode errors as JSON data followed by a newline.
+//
+// Errors are encoded using the error's MarshalJSON method, so the package's
+// JSON configuration applies.
+type JSONEncoder struct{}
+
+// Encode encodes the error, e, and writes the result to w.
+func (enc JSONEncoder) Encode(w io.Writer, e Error) error {
+	return json.NewEncoder(w).Encode(e)
+}
+
+// Non-exported functions
+
+// newErrorEncoderNode creates and returns a new encoder node from the full
+// JSON representation of the error, e, with the fields that are included when
+// errors are logged. The node's string values are redacted.
+//
+// Errors are encoded with the minimal field set unless a JSON field set has
+// been selected, so process and memory statistics are only encoded on request.
+func newErrorEncoderNode(e Error) (*encoderNode, error) {
+	n, err := newEncoderNode(newJSONWErrorFull(
+		e,
+		packageState.config.logFieldSet(),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	n.redact()
+	return n, nil
+}
@@ -0,0 +1,91 @@
+package wrappederror
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+)
+
+func testEncoderError() *Error {
+	return NewWithOptions(
+		New(errors.New("root cause"), "error 1"),
+		"error 2",
+		WithoutProcess(),
+		WithoutSourceFragment(),
+	)
+}
+
+func TestEncoderFunc(t *testing.T) {
+	called := false
+	f := EncoderFunc(func(w io.Writer, e Error) error {
+		called = true
+		return nil
+	})
+
+	if err := f.Encode(io.Discard, *testEncoderError()); err != nil {
+		t.Errorf("Unexpected error: %s\n", err)
+	}
+	if !called {
+		t.Error("Expected the function to be called.")
+	}
+}
+
+func TestJSONEncoder(t *testing.T) {
+	e := testEncoderError()
+
+	var b bytes.Buffer
+	if err := (JSONEncoder{}).Encode(&b, *e); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	data, _ := json.Marshal(e)
+	if b.String() != string(data)+"\n" {
+		t.Errorf("Expected %s but received %s.\n", data, b.String())
+	}
+}
+
+func TestErrorEncode(t *testing.T) {
+	e := testEncoderError()
+	for _, name := range []string{
+		EncoderNameJSON,
+		EncoderNameLogfmt,
+		EncoderNameText,
+		EncoderNameCBOR,
+	} {
+		var b bytes.Buffer
+		if err := e.Encode(&b, name); err != nil {
+			t.Errorf("Unexpected error encoding %s: %s\n", name, err)
+		}
+		if b.Len() == 0 {
+			t.Errorf("Expected %s data.\n", name)
+		}
+	}
+
+	if err := e.Encode(io.Discard, "unknown"); err != ErrEncoderNotFound {
+		t.Errorf("Expected %s but received %v.\n", ErrEncoderNotFound, err)
+	}
+}
+
+func TestNewErrorEncoderNode(t *testing.T) {
+	n, err := newErrorEncoderNode(*testEncoderError())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	var keys []string
+	for _, f := range n.fields {
+		keys = append(keys, f.key)
+	}
+
+	ex := []string{"caller", "metadata", "context", "depth", "wraps"}
+	if len(keys) != len(ex) {
+		t.Fatalf("Expected keys %v but received %v.\n", ex, keys)
+	}
+	for i, k := range keys {
+		if k != ex[i] {
+			t.Errorf("Expected key %s but received %s.\n", ex[i], k)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package wrappederror
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// encoderNodeKind types identify the kind of value stored in an encoder node.
+type encoderNodeKind int
+
+// A group of encoder node kinds.
+const (
+	encoderNodeKindNull encoderNodeKind = iota
+	encoderNodeKindBool
+	encoderNodeKindNumber
+	encoderNodeKindString
+	encoderNodeKindArray
+	encoderNodeKindObject
+)
+
+// encoderNode types are ordered trees of values that encoders walk to emit
+// errors.
+//
+// Nodes are created from the JSON representation of a value, so every encoder
+// shares the same field model, and the same field order, as the package's JSON
+// error types.
+type encoderNode struct {
+
+	// The kind of value stored in the node.
+	kind encoderNodeKind
+
+	// The node's scalar value. Numbers are stored as json.Number values.
+	value interface{}
+
+	// The node's array items.
+	items []*encoderNode
+
+	// The node's object fields in order.
+	fields []encoderField
+}
+
+// encoderField types are the key-value pairs of an object encoder node.
+type encoderField struct {
+	key  string
+	node *encoderNode
+}
+
+// Initializers
+
+// newEncoderNode creates and returns a new encoder node from the JSON
+// representation of v.
+func newEncoderNode(v interface{}) (*encoderNode, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return decodeEncoderNode(d)
+}
+
+// decodeEncoderNode decodes the next value from the decoder, d, in to a node.
+func decodeEncoderNode(d *json.Decoder) (*encoderNode, error) {
+	t, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := t.(type) {
+	case nil:
+		return &encoderNode{kind: encoderNodeKindNull}, nil
+	case bool:
+		return &encoderNode{kind: encoderNodeKindBool, value: v}, nil
+	case json.Number:
+		return &encoderNode{kind: encoderNodeKindNumber, value: v}, nil
+	case string:
+		return &encoderNode{kind: encoderNodeKindString, value: v}, nil
+	case json.Delim:
+		switch v {
+		case '[':
+			n := &encoderNode{kind: encoderNodeKindArray}
+			for d.More() {
+				item, err := decodeEncoderNode(d)
+				if err != nil {
+					return nil, err
+				}
+				n.items = append(n.items, item)
+			}
+			_, err := d.Token()
+			return n, err
+		case '{':
+			n := &encoderNode{kind: encoderNodeKindObject}
+			for d.More() {
+				kt, err := d.Token()
+				if err != nil {
+					return nil, err
+				}
+				item, err := decodeEncoderNode(d)
+				if err != nil {
+					return nil, err
+				}
+				n.fields = append(n.fields, encoderField{kt.(string), item})
+			}
+			_, err := d.Token()
+			return n, err
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected JSON token %v", t)
+}
+
+// Non-exported methods
+
+// field returns the node of the object field with the given key, or nil if one
+// doesn't exist.
+func (n encoderNode) field(key string) *encoderNode {
+	for _, f := range n.fields {
+		if f.key == key {
+			return f.node
+		}
+	}
+	return nil
+}
+
+// redact replaces the matches of the configured redaction patterns in the
+// node's string values and in the string values of its descendants.
+func (n *encoderNode) redact() {
+	switch n.kind {
+	case encoderNodeKindString:
+		n.value = redactString(n.value.(string))
+	case encoderNodeKindArray:
+		for _, item := range n.items {
+			item.redact()
+		}
+	case encoderNodeKindObject:
+		for _, f := range n.fields {
+			f.node.redact()
+		}
+	}
+}
+
+// scalarString returns the string representation of a scalar node.
+func (n encoderNode) scalarString() string {
+	switch n.kind {
+	case encoderNodeKindNull:
+		return "null"
+	case encoderNodeKindBool:
+		return fmt.Sprintf("%t", n.value)
+	case encoderNodeKindNumber:
+		return n.value.(json.Number).String()
+	case encoderNodeKindString:
+		return n.value.(string)
+	case encoderNodeKindArray:
+		return "[]"
+	default:
+		return "{}"
+	}
+}
+
+// JSON Marshaler interface methods
+
+// MarshalJSON marshals the node in to JSON data with its fields in order.
+func (n encoderNode) MarshalJSON() ([]byte, error) {
+	switch n.kind {
+	case encoderNodeKindString:
+		return json.Marshal(n.value)
+	case encoderNodeKindArray:
+		b := []byte("[")
+		for i, item := range n.items {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			ib, err := item.MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+			b = append(b, ib...)
+		}
+		return append(b, ']'), nil
+	case encoderNodeKindObject:
+		b := []byte("{")
+		for i, f := range n.fields {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			kb, err := json.Marshal(f.key)
+			if err != nil {
+				return nil, err
+			}
+			fb, err := f.node.MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+			b = append(append(append(b, kb...), ':'), fb...)
+		}
+		return append(b, '}'), nil
+	default:
+		return []byte(n.scalarString()), nil
+	}
+}
@@ -0,0 +1,93 @@
+package wrappederror
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewEncoderNode(t *testing.T) {
+	v := struct {
+		B bool                   `json:"b"`
+		N int                    `json:"n"`
+		S string                 `json:"s"`
+		A []interface{}          `json:"a"`
+		O map[string]interface{} `json:"o"`
+		Z interface{}            `json:"z"`
+	}{true, 42, "str", []interface{}{1, "2"}, map[string]interface{}{"k": 1.5}, nil}
+
+	n, err := newEncoderNode(v)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	if n.kind != encoderNodeKindObject || len(n.fields) != 6 {
+		t.Fatalf("Unexpected node %+v.\n", n)
+	}
+
+	t.Run("Encoder node 0", func(t *testing.T) {
+		testEncoderNode(t, n.field("b"), encoderNodeKindBool, "true")
+	})
+	t.Run("Encoder node 1", func(t *testing.T) {
+		testEncoderNode(t, n.field("n"), encoderNodeKindNumber, "42")
+	})
+	t.Run("Encoder node 2", func(t *testing.T) {
+		testEncoderNode(t, n.field("s"), encoderNodeKindString, "str")
+	})
+	t.Run("Encoder node 3", func(t *testing.T) {
+		testEncoderNode(t, n.field("a"), encoderNodeKindArray, "[]")
+	})
+	t.Run("Encoder node 4", func(t *testing.T) {
+		testEncoderNode(t, n.field("o").field("k"), encoderNodeKindNumber, "1.5")
+	})
+	t.Run("Encoder node 5", func(t *testing.T) {
+		testEncoderNode(t, n.field("z"), encoderNodeKindNull, "null")
+	})
+
+	if len(n.field("a").items) != 2 {
+		t.Errorf("Unexpected items %+v.\n", n.field("a").items)
+	}
+	if n.field("x") != nil {
+		t.Error("Unexpected field.")
+	}
+}
+
+func testEncoderNode(
+	t *testing.T,
+	n *encoderNode,
+	k encoderNodeKind,
+	s string,
+) {
+	if n == nil {
+		t.Fatal("Expected a node.")
+	}
+	if n.kind != k {
+		t.Errorf("Expected kind %d but received %d.\n", k, n.kind)
+	}
+	if n.scalarString() != s {
+		t.Errorf("Expected \"%s\" but received \"%s\".\n", s, n.scalarString())
+	}
+}
+
+func TestNewEncoderNodeFails(t *testing.T) {
+	if _, err := newEncoderNode(json.RawMessage("{")); err == nil {
+		t.Error("Expected error.")
+	}
+}
+
+func TestEncoderNodeMarshalJSON(t *testing.T) {
+	data := `{"z":1,"a":[true,null,"x\"<"],"m":{"k":1.50}}`
+	n, err := newEncoderNode(json.RawMessage(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	b, err := json.Marshal(n)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	e := `{"z":1,"a":[true,null,"x\"\u003c"],"m":{"k":1.50}}`
+	if string(b) != e {
+		t.Errorf("Expected %s but received %s.\n", e, string(b))
+	}
+}
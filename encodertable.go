@@ -0,0 +1,56 @@
+package wrappederror
+
+import "sync"
+
+// encoderTable keeps track of named encoders.
+type encoderTable struct {
+	encoders      map[string]Encoder
+	encodersMutex *sync.RWMutex
+}
+
+// Initializers
+
+// newEncoderTable creates and returns a new encoder table with the package's
+// built-in encoders registered.
+func newEncoderTable() *encoderTable {
+	return &encoderTable{
+		encoders: map[string]Encoder{
+			EncoderNameJSON:   JSONEncoder{},
+			EncoderNameLogfmt: LogfmtEncoder{},
+			EncoderNameText:   TextEncoder{},
+			EncoderNameCBOR:   CBOREncoder{},
+		},
+		encodersMutex: new(sync.RWMutex),
+	}
+}
+
+// Methods
+
+// register registers the encoder with the given name. If an encoder has already
+// been registered with the name, then it returns an
+// ErrEncoderAlreadyRegistered error.
+func (t *encoderTable) register(name string, encoder Encoder) error {
+	t.encodersMutex.Lock()
+	defer t.encodersMutex.Unlock()
+
+	if _, ok := t.encoders[name]; ok {
+		return ErrEncoderAlreadyRegistered
+	}
+
+	t.encoders[name] = encoder
+	return nil
+}
+
+// unregister unregisters the encoder with the given name.
+func (t *encoderTable) unregister(name string) {
+	t.encodersMutex.Lock()
+	defer t.encodersMutex.Unlock()
+	delete(t.encoders, name)
+}
+
+// encoder returns the encoder with the given name, or nil if one doesn't exist.
+func (t *encoderTable) encoder(name string) Encoder {
+	t.encodersMutex.RLock()
+	defer t.encodersMutex.RUnlock()
+	return t.encoders[name]
+}
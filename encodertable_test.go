@@ -0,0 +1,35 @@
+package wrappederror
+
+import "testing"
+
+func TestNewEncoderTable(t *testing.T) {
+	et := newEncoderTable()
+	for _, name := range []string{
+		EncoderNameJSON,
+		EncoderNameLogfmt,
+		EncoderNameText,
+		EncoderNameCBOR,
+	} {
+		if et.encoder(name) == nil {
+			t.Errorf("Expected encoder %s.\n", name)
+		}
+	}
+}
+
+func TestEncoderTableRegister(t *testing.T) {
+	et := newEncoderTable()
+	if err := et.register("test", TextEncoder{}); err != nil {
+		t.Errorf("Unexpected error: %s\n", err)
+	}
+	if err := et.register("test", TextEncoder{}); err != ErrEncoderAlreadyRegistered {
+		t.Errorf("Expected %s but received %v.\n", ErrEncoderAlreadyRegistered, err)
+	}
+	if et.encoder("test") == nil {
+		t.Error("Expected an encoder.")
+	}
+
+	et.unregister("test")
+	if et.encoder("test") != nil {
+		t.Error("Unexpected encoder.")
+	}
+}
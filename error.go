@@ -1,9 +1,13 @@
 package wrappederror
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
 )
 
 // Error structure string constants.
@@ -12,6 +16,8 @@ var (
 	errorTraceFirstItemDecoration  string = "┌"
 	errorTraceMiddleItemDecoration string = "├"
 	errorTraceLastItemDecoration   string = "└"
+	errorTraceBranchDecoration     string = "│"
+	errorTraceBranchIndent         string = "  "
 )
 
 // Error types wrap an error and provide context, caller and process
@@ -63,52 +69,143 @@ type Error struct {
 
 // New creates and returns a new error with an inner error and context.
 func New(err error, ctx interface{}) *Error {
-	var caller *Caller
-	if packageState.config.CaptureCaller() {
-		caller = newCaller(
-			2,
-			packageState.config.CaptureSourceFragments(),
-			packageState.config.SourceFragmentRadius(),
-		)
+	return newError(err, ctx, newOptions(packageState.config, nil))
+}
+
+// NewWithOptions creates and returns a new error with an inner error, context
+// and options.
+//
+// Options override the package's configuration for the new error only.
+func NewWithOptions(err error, ctx interface{}, opts ...Option) *Error {
+	return newError(err, ctx, newOptions(packageState.config, opts))
+}
+
+// NewCtx creates and returns a new error with an inner error and context, c,
+// and fields extracted from the context, ctx.
+//
+// The error's fields contain the values extracted by the package's context
+// extractors. If err was caused by ctx being canceled or its deadline passing,
+// then the fields also contain ctx's deadline and the offset from it.
+func NewCtx(ctx context.Context, err error, c interface{}) *Error {
+	return newError(err, c, newOptions(
+		packageState.config,
+		[]Option{WithContext(ctx)},
+	))
+}
+
+// NewSkip creates and returns a new error with an inner error and context.
+//
+// The skip parameter is the number of additional stack frames to skip when
+// capturing the error's call information. Use it from functions that wrap New
+// so that the error's caller identifies the meaningful call site. Negative
+// skips are treated as 0.
+func NewSkip(skip int, err error, ctx interface{}) *Error {
+	return newError(err, ctx, newOptions(
+		packageState.config,
+		[]Option{WithSkip(skip)},
+	))
+}
+
+// Join creates and returns a new error with context that wraps every non-nil
+// error in errs.
+//
+// The returned error's Unwrap method returns an error that implements
+// Unwrap() []error, so the standard library's errors.Is and errors.As functions
+// examine every joined error. The error's chain, depth, trace and JSON describe
+// a tree with the joined errors as its branches.
+func Join(errs []error, ctx interface{}) *Error {
+	return newError(
+		newMultiError(errs),
+		ctx,
+		newOptions(packageState.config, nil),
+	)
+}
+
+// newError creates and returns a new error using the options, o. It must be
+// called directly by an exported initializer.
+func newError(err error, ctx interface{}, o *options) *Error {
+	e := &Error{
+		context: ctx,
+		inner:   err,
+	}
+
+	// Errors are sampled before they capture their call information, unless
+	// their fingerprint is created from it.
+	if o.sampleLimit > 0 && (o.fingerprint != "" || !fingerprintsCaller(o.fingerprinter)) {
+		e.Metadata = newMetadata(*e, o)
 	}
 
-	var process *Process
-	if packageState.config.CaptureProcess() {
-		process = newProcess()
+	if o.captureCaller && (e.Metadata == nil || !e.Metadata.Sampled) {
+		if o.recovered {
+			e.Caller = newPanicCaller(
+				3+o.skip,
+				o.captureSourceFragments,
+				o.sourceFragmentRadius,
+			)
+		} else {
+			e.Caller = newCaller(
+				3+o.skip,
+				o.captureSourceFragments,
+				o.sourceFragmentRadius,
+			)
+		}
 	}
 
-	return &Error{
-		context:  ctx,
-		Caller:   caller,
-		Process:  process,
-		Metadata: newMetadata(err),
-		inner:    err,
+	if e.Metadata == nil {
+		e.Metadata = newMetadata(*e, o)
 	}
+
+	if e.Metadata.Sampled {
+		// Sampled errors only keep their metadata.
+		e.Caller = nil
+	} else if o.captureProcess {
+		e.Process = newProcess(o.captureMemory)
+	}
+
+	packageState.observe(*e)
+	if o.report {
+		packageState.report(*e)
+	}
+	return e
 }
 
+// The reflected type of the error interface.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 // Exported methods
 
-// Format returns a formatted string representation of the error using the error
-// format string, ef.
+// FormatString returns a formatted string representation of the error using
+// the error format string, ef.
 //
 // You create an error format string by building a string with
 // ErrorFormatToken types.
 //
 // Do not use formatting verbs supported by the fmt package in the error
 // format string.
-func (e Error) Format(ef string) string {
-	return newFormatter().format(e, ef)
+func (e Error) FormatString(ef string) string {
+	return redactString(newFormatter().format(e, ef))
+}
+
+// Encode encodes the error with the encoder registered under the given name and
+// writes the result to w.
+//
+// If no encoder has been registered with the name, then an ErrEncoderNotFound
+// error is returned.
+func (e Error) Encode(w io.Writer, name string) error {
+	encoder := packageState.getEncoder(name)
+	if encoder == nil {
+		return ErrEncoderNotFound
+	}
+	return encoder.Encode(w, e)
 }
 
 // Chain returns the error chain as a slice with the receiver at index 0.
+//
+// If an error in the chain wraps more than one error, then the chain contains
+// the errors of the error tree in pre-order. That is, each error is followed by
+// the errors it wraps, in order.
 func (e Error) Chain() []error {
-	var c []error
-	var ce error = e
-	for ce != nil {
-		c = append(c, ce)
-		ce = errors.Unwrap(ce)
-	}
-	return c
+	return errorTree(e)
 }
 
 // Walk calls the step function for each error in the error chain, including the
@@ -123,12 +220,17 @@ func (e Error) Walk(step func(err error) bool) {
 }
 
 // ErrorWithDepth returns the error in the chain with the given depth.
+//
+// If an error in the chain wraps more than one error, then the error is found
+// in the longest branch of the error tree, and its depth is the same value
+// returned by its Depth method. For example, the error with the receiver's
+// depth is the receiver.
 func (e Error) ErrorWithDepth(depth int) error {
-	c := e.Chain()
-	if depth < 0 || depth >= len(c) {
+	b := newWalkTreeNode(e).branch()
+	if depth < 0 || depth >= len(b) {
 		return nil
 	}
-	return c[len(c)-depth-1]
+	return b[len(b)-depth-1]
 }
 
 // ErrorWithIndex returns the error in the chain with the given index. This is
@@ -136,59 +238,34 @@ func (e Error) ErrorWithDepth(depth int) error {
 //
 // For example, if the error chain is [e0, e1, e2] with depths [2, 1, 0], then
 // the error at index 0 is e0, the error at index 1 is e1, and so forth.
+//
+// If an error in the chain wraps more than one error, then the error is found
+// in the longest branch of the error tree, like ErrorWithDepth.
 func (e Error) ErrorWithIndex(index int) error {
-	c := e.Chain()
-	if index < 0 || index >= len(c) {
+	b := newWalkTreeNode(e).branch()
+	if index < 0 || index >= len(b) {
 		return nil
 	}
-	return c[index]
+	return b[index]
 }
 
 // Depth returns the number of nested errors in the receiver. That is, the
 // number of errors after, but not including, this error in the error chain.
 //
 // For example, if an error has no nested errors, then its depth is 0.
+//
+// If an error in the chain wraps more than one error, then the depth is the
+// number of errors in the longest branch of the error tree.
 func (e Error) Depth() int {
-	return len(e.Chain()) - 1
+	return errorHeight(e)
 }
 
 // Trace returns a prettified string representation of the error chain.
+//
+// Errors that wrap more than one error are followed by their branches, which
+// are indented beneath them.
 func (e Error) Trace() string {
-	d := e.Depth()
-	if d == 0 {
-		return fmt.Sprintf("%s %s", e.Caller, e.Error())
-	}
-
-	var msg string
-	c := e.Chain()
-
-	for i, err := range c {
-		end := i == len(c)-1
-		var p string
-		if i == 0 {
-			p = errorTraceFirstItemDecoration
-		} else if end {
-			p = errorTraceLastItemDecoration
-		} else {
-			p = errorTraceMiddleItemDecoration
-		}
-
-		var em string
-		if we, ok := err.(Error); ok {
-			em = fmt.Sprintf("%s %d: %s %+v", p, d-i, we.Caller, we.context)
-		} else if we, ok := err.(*Error); ok {
-			em = fmt.Sprintf("%s %d: %s %+v", p, d-i, we.Caller, we.context)
-		} else {
-			em = fmt.Sprintf("%s %d: %s", p, d-i, err.Error())
-		}
-
-		msg += em
-		if !end {
-			msg += "\n"
-		}
-	}
-
-	return msg
+	return redactString(e.trace())
 }
 
 // Unwrap returns the wrapped error or nil if one doesn't exist.
@@ -199,19 +276,47 @@ func (e Error) Unwrap() error {
 // As finds the first error in the error chain that matches target, and if so,
 // sets target to that error value and returns true. Otherwise, it returns
 // false.
+//
+// The target must be a non-nil pointer to either a type that implements error,
+// or to any interface type. Errors defined by this package match *Error targets
+// and **Error targets, and all other errors in the chain are matched by their
+// type or by their own As methods. If target is invalid, then As returns false.
 func (e Error) As(target interface{}) bool {
+	if target == nil {
+		return false
+	}
+
+	val := reflect.ValueOf(target)
+	typ := val.Type()
+	if typ.Kind() != reflect.Ptr || val.IsNil() {
+		return false
+	}
+
+	targetType := typ.Elem()
+	if targetType.Kind() != reflect.Interface &&
+		!targetType.Implements(errorType) {
+		return false
+	}
+
 	as := false
+	e.Walk(func(err error) bool {
+		if v, ok := errorAsValue(err, targetType); ok {
+			val.Elem().Set(v)
+			as = true
+			return false
+		}
 
-	if we, ok := target.(*Error); ok {
-		e.Walk(func(err error) bool {
-			if wei, ok := err.(Error); ok && wei.Error() == we.Error() {
-				target = &wei
-				as = true
-				return false
-			}
+		if _, ok := asError(err); ok {
 			return true
-		})
-	}
+		}
+
+		if x, ok := err.(interface{ As(interface{}) bool }); ok && x.As(target) {
+			as = true
+			return false
+		}
+
+		return true
+	})
 
 	return as
 }
@@ -221,51 +326,357 @@ func (e Error) As(target interface{}) bool {
 // The chain consists of the receiver followed by the sequence of errors
 // obtained by repeatedly calling Unwrap.
 //
-// The receiver considered to match a target if it is equal to that target or
-// if it implements a method Is(error) bool such that Is(target) returns true.
+// Errors defined by this package match a target if they are the same error,
+// that is, they were created by the same call to New. All other errors match a
+// target if they are equal to that target or if they implement a method
+// Is(error) bool such that Is(target) returns true.
 func (e Error) Is(target error) bool {
+	if target == nil {
+		return false
+	}
+
+	isComparable := reflect.TypeOf(target).Comparable()
+	twe, targetIsError := asError(target)
+
 	is := false
 	e.Walk(func(err error) bool {
-		if err.Error() == target.Error() {
+		if we, ok := asError(err); ok {
+			if targetIsError && we.same(twe) {
+				is = true
+				return false
+			}
+			return true
+		}
+
+		if isComparable && err == target {
 			is = true
 			return false
 		}
+
+		if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(target) {
+			is = true
+			return false
+		}
+
 		return true
 	})
+
 	return is
 }
 
 // Context returns the error's context.
+//
+// The context is returned as it was given to the error, without redaction.
 func (e Error) Context() interface{} {
 	return e.context
 }
 
+// Code returns the error's code, or an empty string if it doesn't have one.
+func (e Error) Code() ErrorCode {
+	if e.Metadata == nil {
+		return ""
+	}
+	return e.Metadata.Code
+}
+
+// Kind returns the error's kind, or an empty string if it doesn't have one.
+func (e Error) Kind() ErrorKind {
+	if e.Metadata == nil {
+		return ""
+	}
+	return e.Metadata.Kind
+}
+
+// Fields returns a copy of the request-scoped fields extracted from the
+// context.Context that the error was created with, or nil if it doesn't have
+// any.
+//
+// Like the context, the fields are returned without redaction.
+func (e Error) Fields() map[string]interface{} {
+	fields := e.fields()
+	if len(fields) == 0 {
+		return nil
+	}
+
+	c := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		c[k] = v
+	}
+	return c
+}
+
 // Error interface methods
 
 func (e Error) Error() string {
-	var s string
-	c := e.Chain()
-
-	for i, err := range c {
-		if we, ok := err.(Error); ok {
-			s += fmt.Sprintf("%+v", we.context)
-		} else if we, ok := err.(*Error); ok {
-			s += fmt.Sprintf("%+v", we.context)
+	s := fmt.Sprintf("%+v", redactContext(e.context))
+	if e.inner != nil {
+		s += errorChainDelimiter + e.inner.Error()
+	}
+	return redactString(s)
+}
+
+// Non-exported methods
+
+// fields returns the error's fields, or nil if it doesn't have metadata.
+func (e Error) fields() map[string]interface{} {
+	if e.Metadata == nil {
+		return nil
+	}
+	return e.Metadata.Fields
+}
+
+// trace returns the error's trace without redacting it.
+func (e Error) trace() string {
+	seq := traceSequence(e)
+	if len(seq) == 1 && len(errorChildren(e)) == 0 {
+		if fields := e.fields(); len(fields) > 0 {
+			return fmt.Sprintf("%s %s [%s]", e.Caller, e.Error(), fieldsString(fields))
+		}
+		return fmt.Sprintf("%s %s", e.Caller, e.Error())
+	}
+
+	var lines []string
+	for i, err := range seq {
+		var p string
+		if i == 0 {
+			p = errorTraceFirstItemDecoration
+		} else if i == len(seq)-1 {
+			p = errorTraceLastItemDecoration
 		} else {
-			s += err.Error()
+			p = errorTraceMiddleItemDecoration
 		}
 
-		if i < len(c)-1 {
-			s += errorChainDelimiter
+		lines = append(lines, traceLine(p, err))
+	}
+
+	if branches := errorChildren(seq[len(seq)-1]); len(branches) > 1 {
+		lines = appendTraceBranches(lines, branches, errorTraceBranchIndent)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// same returns whether or not the receiver and we are the same error. Errors are
+// the same if they share metadata, which is created once for each error.
+func (e Error) same(we Error) bool {
+	return e.Metadata != nil && e.Metadata == we.Metadata
+}
+
+// Non-exported functions
+
+// asError returns the error, err, as an Error if it is either an Error or a
+// non-nil *Error.
+func asError(err error) (Error, bool) {
+	switch we := err.(type) {
+	case Error:
+		return we, true
+	case *Error:
+		if we != nil {
+			return *we, true
 		}
 	}
+	return Error{}, false
+}
+
+// errorAsValue returns the error, err, as a value that is assignable to the
+// target type. Error values are converted to *Error values, and *Error values
+// to Error values, when necessary.
+func errorAsValue(err error, targetType reflect.Type) (reflect.Value, bool) {
+	v := reflect.ValueOf(err)
+	if v.Type().AssignableTo(targetType) {
+		return v, true
+	}
 
-	return s
+	switch we := err.(type) {
+	case Error:
+		p := reflect.ValueOf(&we)
+		if p.Type().AssignableTo(targetType) {
+			return p, true
+		}
+	case *Error:
+		if we != nil {
+			ev := reflect.ValueOf(*we)
+			if ev.Type().AssignableTo(targetType) {
+				return ev, true
+			}
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// traceSequence returns err followed by the errors it wraps until an error
+// wraps either no errors or more than one error.
+func traceSequence(err error) []error {
+	seq := []error{err}
+	for {
+		c := errorChildren(seq[len(seq)-1])
+		if len(c) != 1 {
+			return seq
+		}
+		seq = append(seq, c[0])
+	}
+}
+
+// traceLine returns the trace line of err with the decoration, p.
+func traceLine(p string, err error) string {
+	d := errorHeight(err)
+	if we, ok := asError(err); ok {
+		l := fmt.Sprintf("%s %d: %s %+v", p, d, we.Caller, redactContext(we.context))
+		if fields := we.fields(); len(fields) > 0 {
+			l += fmt.Sprintf(" [%s]", fieldsString(fields))
+		}
+		return l
+	}
+	return fmt.Sprintf("%s %d: %s", p, d, err.Error())
+}
+
+// appendTraceBranches appends the trace lines of each branch to lines with the
+// given prefix and returns the result.
+func appendTraceBranches(lines []string, branches []error, prefix string) []string {
+	for i, b := range branches {
+		p := errorTraceMiddleItemDecoration
+		cont := errorTraceBranchDecoration + " "
+		if i == len(branches)-1 {
+			p = errorTraceLastItemDecoration
+			cont = errorTraceBranchIndent
+		}
+
+		seq := traceSequence(b)
+		lines = append(lines, prefix+traceLine(p, seq[0]))
+
+		for j, err := range seq[1:] {
+			sp := errorTraceMiddleItemDecoration
+			if j == len(seq)-2 {
+				sp = errorTraceLastItemDecoration
+			}
+			lines = append(lines, prefix+cont+traceLine(sp, err))
+		}
+
+		if c := errorChildren(seq[len(seq)-1]); len(c) > 1 {
+			cp := prefix + cont
+			if len(seq) > 1 {
+				cp += errorTraceBranchIndent
+			}
+			lines = appendTraceBranches(lines, c, cp)
+		}
+	}
+
+	return lines
+}
+
+// fmt Formatter interface methods
+
+// Format formats the error according to the fmt.Formatter interface.
+//
+// The %s and %v verbs print the error chain, and %q prints the error chain as a
+// quoted string. The %+v verb prints the error chain followed by the error's
+// trace and the stack frames of its caller. The %#v verb prints a Go-syntax
+// representation of the error.
+//
+// The output of every verb is redacted.
+func (e Error) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			var b strings.Builder
+			b.WriteString(e.Error())
+			b.WriteString("\n")
+			b.WriteString(e.trace())
+			if e.Caller != nil {
+				for _, frame := range e.Caller.Frames() {
+					b.WriteString("\n")
+					b.WriteString(frame.String())
+				}
+			}
+			io.WriteString(f, redactString(b.String()))
+			return
+		}
+
+		if f.Flag('#') {
+			m := e.Metadata
+			if m != nil && len(m.Fields) > 0 {
+				rm := *m
+				rm.Fields = redactFields(m.Fields)
+				m = &rm
+			}
+
+			io.WriteString(f, redactString(fmt.Sprintf(
+				"wrappederror.Error{Caller:%#v, Process:%#v, Metadata:%#v, "+
+					"context:%#v, inner:%#v}",
+				e.Caller,
+				e.Process,
+				m,
+				redactContext(e.context),
+				e.inner,
+			)))
+			return
+		}
+
+		io.WriteString(f, e.Error())
+	case 's':
+		io.WriteString(f, e.Error())
+	case 'q':
+		fmt.Fprintf(f, "%q", e.Error())
+	default:
+		fmt.Fprintf(f, "%%!%c(wrappederror.Error=%s)", verb, e.Error())
+	}
+}
+
+// slog LogValuer interface methods
+
+// LogValue returns the error as a group of slog attributes.
+//
+// The group contains the fields that are included when the error is marshaled
+// in to JSON. If the package is configured to marshal minimal JSON, then the
+// group contains the JSONFieldSetMinimal fields.
+func (e Error) LogValue() slog.Value {
+	return newSlogValue(e, packageState.config.logFieldSet())
+}
+
+// LogValueWith returns the error as a group of slog attributes that only
+// contains the given fields, regardless of the package's JSON configuration.
+func (e Error) LogValueWith(fields JSONFieldSet) slog.Value {
+	return newSlogValue(e, fields)
 }
 
 // JSON Marshaler interface methods
 
-// MarshalJSON marshals the error in to JSON data.
+// MarshalJSON marshals the error in to JSON data. The data's string values are
+// redacted.
 func (e Error) MarshalJSON() ([]byte, error) {
-	return json.Marshal(newJSONWError(e))
+	data, err := json.Marshal(newJSONWError(
+		e,
+		packageState.config.JSONFieldSet(),
+		packageState.config.MarshalMinimalJSON(),
+	))
+	if err != nil {
+		return nil, err
+	}
+	return redactJSON(data)
+}
+
+// MarshalJSONWith marshals the error in to a full JSON object that only
+// contains the given fields, regardless of the package's JSON configuration.
+// The data's string values are redacted.
+func (e Error) MarshalJSONWith(fields JSONFieldSet) ([]byte, error) {
+	data, err := json.Marshal(newJSONWError(e, fields, false))
+	if err != nil {
+		return nil, err
+	}
+	return redactJSON(data)
+}
+
+// UnmarshalJSON unmarshals JSON data created by MarshalJSON in to the error.
+// Both the minimal and full versions of JSON errors are accepted.
+//
+// Wrapped errors that were created by this package are restored as *Error
+// types with their caller, process and metadata. All other wrapped errors are
+// restored as *RemoteError types that preserve their message.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var j jsonAnyError
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	return decodeJSONWError(j, e)
 }
@@ -51,6 +51,27 @@ func TestNewError(t *testing.T) {
 	})
 }
 
+func TestNewSkipError(t *testing.T) {
+	e := testNewSkipError()
+	if e.Caller.Function != "github.com/colinc86/wrappederror.TestNewSkipError" {
+		t.Errorf("Unexpected caller %s.\n", e.Caller)
+	}
+
+	e = NewSkip(0, nil, "test")
+	if e.Caller.Function != "github.com/colinc86/wrappederror.TestNewSkipError" {
+		t.Errorf("Unexpected caller %s.\n", e.Caller)
+	}
+
+	e = NewSkip(-2, nil, "test")
+	if e.Caller.Function != "github.com/colinc86/wrappederror.TestNewSkipError" {
+		t.Errorf("Unexpected caller %s.\n", e.Caller)
+	}
+}
+
+func testNewSkipError() *Error {
+	return NewSkip(1, nil, "test")
+}
+
 func testErrorMessage(t *testing.T, e *Error, s string) {
 	if e.Error() != s {
 		t.Errorf("Expected \"%s\" but received \"%s\".\n", s, e.Error())
@@ -92,8 +113,59 @@ func TestErrorFormat(t *testing.T) {
 }
 
 func testErrorFormat(t *testing.T, e *Error, ef, s string) {
-	if e.Format(ef) != s {
-		t.Errorf("Expected \"%s\" but received \"%s\".\n", s, e.Format(ef))
+	if e.FormatString(ef) != s {
+		t.Errorf("Expected \"%s\" but received \"%s\".\n", s, e.FormatString(ef))
+	}
+}
+
+func TestErrorFormatVerbs(t *testing.T) {
+	e := testErrors.e1
+	t.Run("Error format verbs 0", func(t *testing.T) {
+		testErrorFormatVerb(t, e, "%v", e.Error())
+	})
+	t.Run("Error format verbs 1", func(t *testing.T) {
+		testErrorFormatVerb(t, e, "%s", e.Error())
+	})
+	t.Run("Error format verbs 2", func(t *testing.T) {
+		testErrorFormatVerb(t, e, "%q", "\"error 1: error 0\"")
+	})
+	t.Run("Error format verbs 3", func(t *testing.T) {
+		testErrorFormatVerb(t, e, "%d", "%!d(wrappederror.Error=error 1: error 0)")
+	})
+	t.Run("Error format verbs 4", func(t *testing.T) {
+		testErrorFormatVerb(t, *e, "%v", e.Error())
+	})
+}
+
+func testErrorFormatVerb(t *testing.T, e interface{}, f, s string) {
+	if r := fmt.Sprintf(f, e); r != s {
+		t.Errorf("Expected \"%s\" but received \"%s\".\n", s, r)
+	}
+}
+
+func TestErrorFormatVerbPlus(t *testing.T) {
+	e := testErrors.e1
+	s := fmt.Sprintf("%+v", e)
+	ex := e.Error() + "\n" + e.Trace() + "\n" + e.Caller.Frames()[0].String()
+
+	if !strings.HasPrefix(s, ex) {
+		t.Errorf("Expected prefix \"%s\" but received \"%s\".\n", ex, s)
+	}
+
+	if n := strings.Count(s, "\n\t"); n != len(e.Caller.Frames()) {
+		t.Errorf("Expected %d but received %d.\n", len(e.Caller.Frames()), n)
+	}
+}
+
+func TestErrorFormatVerbSharp(t *testing.T) {
+	s := fmt.Sprintf("%#v", testErrors.e1)
+	if !strings.HasPrefix(s, "wrappederror.Error{Caller:&wrappederror.Caller{") {
+		t.Errorf("Unexpected Go-syntax representation \"%s\".\n", s)
+	}
+
+	ex := "context:\"error 1\", inner:wrappederror.Error{"
+	if !strings.Contains(s, ex) {
+		t.Errorf("Expected \"%s\" in \"%s\".\n", ex, s)
 	}
 }
 
@@ -126,6 +198,87 @@ func testErrorChain(t *testing.T, e *Error, i int, s []*Error) {
 	}
 }
 
+func TestJoin(t *testing.T) {
+	a := errors.New("a")
+	b := New(errors.New("c"), "b")
+	e := New(Join([]error{a, nil, b}, "joined"), "outer")
+
+	ex := "outer: joined: [a; b: c]"
+	if e.Error() != ex {
+		t.Errorf("Expected %s but received %s.\n", ex, e.Error())
+	}
+
+	if e.Depth() != 3 {
+		t.Errorf("Expected %d but received %d.\n", 3, e.Depth())
+	}
+
+	if len(e.Chain()) != 5 {
+		t.Errorf("Expected %d but received %d.\n", 5, len(e.Chain()))
+	}
+
+	if !errors.Is(e, a) || !errors.Is(e, b) {
+		t.Error("Expected joined errors to match.")
+	}
+
+	var target *Error
+	if !errors.As(e.ErrorWithIndex(1), &target) || target.Context() != "joined" {
+		t.Error("Expected joined error.")
+	}
+
+	if Join(nil, "empty").Unwrap() != nil {
+		t.Error("Expected nil.")
+	}
+}
+
+func TestJoinTrace(t *testing.T) {
+	e := Join([]error{
+		New(errors.New("timeout"), "worker 1"),
+		errors.New("refused"),
+	}, "fan out")
+
+	lines := strings.Split(e.Trace(), "\n")
+	prefixes := []string{"┌ 2: ", "  ├ 1: ", "  │ └ 0: timeout", "  └ 0: refused"}
+	if len(lines) != len(prefixes) {
+		t.Fatalf("Expected %d but received %d.\n", len(prefixes), len(lines))
+	}
+
+	for i, p := range prefixes {
+		if !strings.HasPrefix(lines[i], p) {
+			t.Errorf("Expected prefix \"%s\" but received \"%s\".\n", p, lines[i])
+		}
+	}
+}
+
+func TestJoinJSON(t *testing.T) {
+	e := Join([]error{errors.New("a"), New(nil, "b")}, "joined")
+	data, err := e.MarshalJSONWith(JSONFieldSetMinimal)
+	if err != nil {
+		t.Fatalf("Error marshaling JSON: %s\n", err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Error unmarshaling JSON: %s\n", err)
+	}
+
+	if wraps, ok := m[jsonKeyWraps].([]interface{}); !ok || len(wraps) != 2 {
+		t.Fatalf("Expected array of 2 but received %v.\n", m[jsonKeyWraps])
+	}
+
+	var d Error
+	if err := d.UnmarshalJSON(data); err != nil {
+		t.Fatalf("Error unmarshaling error: %s\n", err)
+	}
+
+	if d.Error() != e.Error() {
+		t.Errorf("Expected %s but received %s.\n", e.Error(), d.Error())
+	}
+
+	if _, ok := d.Unwrap().(*multiError); !ok {
+		t.Error("Expected multi-error.")
+	}
+}
+
 func TestErrorWalk(t *testing.T) {
 	t.Run("Error chain 0", func(t *testing.T) { testErrorWalk(t, testErrors.e0) })
 	t.Run("Error chain 1", func(t *testing.T) { testErrorWalk(t, testErrors.e1) })
@@ -186,6 +339,30 @@ func testErrorErrorWithDepth(t *testing.T, e *Error, i int, err error) {
 	}
 }
 
+func TestErrorWithDepthJoin(t *testing.T) {
+	a := New(errors.New("a"), "A")
+	b := errors.New("b")
+	e := Join([]error{a, b}, "joined")
+
+	if e.Depth() != 2 {
+		t.Fatalf("Expected %d but received %d.\n", 2, e.Depth())
+	}
+
+	t.Run("Error with depth 0", func(t *testing.T) {
+		testErrorErrorWithDepth(t, e, 0, a.Unwrap())
+	})
+	t.Run("Error with depth 1", func(t *testing.T) {
+		testErrorErrorWithDepth(t, e, 1, a)
+	})
+	t.Run("Error with depth 2", func(t *testing.T) {
+		testErrorErrorWithDepth(t, e, 2, e)
+	})
+
+	if e.ErrorWithDepth(3) != nil {
+		t.Error("Expected no error.")
+	}
+}
+
 func TestErrorWithIndexBadIndex_1(t *testing.T) {
 	if testErrors.e2.ErrorWithIndex(-1) != nil {
 		t.Error("Expected no error.")
@@ -210,6 +387,26 @@ func TestErrorErrorWithIndex(t *testing.T) {
 	})
 }
 
+func TestErrorWithIndexJoin(t *testing.T) {
+	a := errors.New("a")
+	b := New(errors.New("c"), "b")
+	e := Join([]error{a, b}, "joined")
+
+	t.Run("Error with index 0", func(t *testing.T) {
+		testErrorErrorWithIndex(t, e, 0, e)
+	})
+	t.Run("Error with index 1", func(t *testing.T) {
+		testErrorErrorWithIndex(t, e, 1, b)
+	})
+	t.Run("Error with index 2", func(t *testing.T) {
+		testErrorErrorWithIndex(t, e, 2, b.Unwrap())
+	})
+
+	if e.ErrorWithIndex(3) != nil {
+		t.Error("Expected no error.")
+	}
+}
+
 func testErrorErrorWithIndex(t *testing.T, e *Error, i int, err error) {
 	ewd := e.ErrorWithIndex(i)
 	if ewd.Error() != err.Error() {
@@ -321,7 +518,13 @@ func TestErrorAs(t *testing.T) {
 	ee1 := New(e, "error 1")
 	ee2 := New(ee1, "error 2")
 	t.Run("Error as 6", func(t *testing.T) {
-		testErrorAs(t, ee2, ee1, false)
+		testErrorAs(t, ee2, new(Error), true)
+	})
+	t.Run("Error as 7", func(t *testing.T) {
+		testErrorAs(t, ee2, nil, false)
+	})
+	t.Run("Error as 8", func(t *testing.T) {
+		testErrorAs(t, ee2, "error", false)
 	})
 }
 
@@ -363,6 +566,127 @@ func testErrorIs(t *testing.T, e *Error, is error, ex bool) {
 	}
 }
 
+func TestErrorAsSetsTarget(t *testing.T) {
+	e := New(New(errors.New("error 0"), "error 1"), "error 2")
+
+	var target *Error
+	if !e.As(&target) {
+		t.Fatal("Expected error.")
+	}
+	if target.Context() != "error 2" {
+		t.Errorf("Expected %s but received %v.\n", "error 2", target.Context())
+	}
+
+	var value Error
+	if !e.As(&value) {
+		t.Fatal("Expected error.")
+	}
+	if value.Context() != "error 2" {
+		t.Errorf("Expected %s but received %v.\n", "error 2", value.Context())
+	}
+
+	var i interface{ Unwrap() error }
+	if !e.As(&i) {
+		t.Fatal("Expected error.")
+	}
+	if _, ok := i.(Error); !ok {
+		t.Errorf("Expected Error but received %T.\n", i)
+	}
+}
+
+// testIsError is an error that matches any testIsError target with the same
+// code.
+type testIsError struct {
+	code int
+}
+
+func (e testIsError) Error() string {
+	return "test is error"
+}
+
+func (e testIsError) Is(target error) bool {
+	t, ok := target.(testIsError)
+	return ok && t.code == e.code
+}
+
+func TestErrorIsAsStandardLibrary(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	pathErr := &os.PathError{Op: "open", Path: "/", Err: os.ErrNotExist}
+	inner := New(sentinel, "inner")
+
+	tests := []struct {
+		name     string
+		err      error
+		std      error
+		isTarget error
+	}{
+		{"sentinel", New(sentinel, "ctx"), fmt.Errorf("ctx: %w", sentinel), sentinel},
+		{"same text", New(sentinel, "ctx"), fmt.Errorf("ctx: %w", sentinel), errors.New("sentinel")},
+		{"is method", New(testIsError{1}, "ctx"), fmt.Errorf("ctx: %w", testIsError{1}), testIsError{1}},
+		{"is method mismatch", New(testIsError{1}, "ctx"), fmt.Errorf("ctx: %w", testIsError{1}), testIsError{2}},
+		{"nested", New(fmt.Errorf("a: %w", pathErr), "ctx"), fmt.Errorf("ctx: %w", fmt.Errorf("a: %w", pathErr)), os.ErrNotExist},
+		{"wrapped error", New(inner, "outer"), fmt.Errorf("outer: %w", inner), inner},
+		{"foreign wrapper", fmt.Errorf("outer: %w", New(sentinel, "ctx")), fmt.Errorf("outer: %w", fmt.Errorf("ctx: %w", sentinel)), sentinel},
+		{"nil target", New(sentinel, "ctx"), fmt.Errorf("ctx: %w", sentinel), nil},
+	}
+
+	for _, test := range tests {
+		t.Run("Error is as "+test.name, func(t *testing.T) {
+			if is, ex := errors.Is(test.err, test.isTarget), errors.Is(test.std, test.isTarget); is != ex {
+				t.Errorf("Expected Is %t but received %t.\n", ex, is)
+			}
+
+			var pe *os.PathError
+			var spe *os.PathError
+			as, ex := errors.As(test.err, &pe), errors.As(test.std, &spe)
+			if as != ex {
+				t.Errorf("Expected As %t but received %t.\n", ex, as)
+			}
+			if pe != spe {
+				t.Errorf("Expected %v but received %v.\n", spe, pe)
+			}
+
+			var ie testIsError
+			var sie testIsError
+			if as, ex := errors.As(test.err, &ie), errors.As(test.std, &sie); as != ex || ie != sie {
+				t.Errorf("Expected As %t (%v) but received %t (%v).\n", ex, sie, as, ie)
+			}
+		})
+	}
+}
+
+func TestErrorIsDistinctErrors(t *testing.T) {
+	e0 := New(nil, "error")
+	e1 := New(nil, "error")
+
+	if errors.Is(e0, e1) {
+		t.Error("Expected distinct errors to not match.")
+	}
+
+	if !errors.Is(New(e0, "outer"), e0) {
+		t.Error("Expected wrapped error to match.")
+	}
+
+	if !errors.Is(*e0, e0) {
+		t.Error("Expected error value to match its pointer.")
+	}
+}
+
+func TestErrorCodeAndKind(t *testing.T) {
+	e := NewWithOptions(nil, "error", WithCode("E1"), WithKind("network"))
+	if e.Code() != "E1" {
+		t.Errorf("Expected %s but received %s.\n", "E1", e.Code())
+	}
+	if e.Kind() != "network" {
+		t.Errorf("Expected %s but received %s.\n", "network", e.Kind())
+	}
+
+	var z Error
+	if z.Code() != "" || z.Kind() != "" {
+		t.Error("Expected empty code and kind.")
+	}
+}
+
 func TestErrorContext(t *testing.T) {
 	t.Run("Error context 0", func(t *testing.T) {
 		testErrorContext(t, testErrors.e0, "error 0")
@@ -401,6 +725,7 @@ func TestErrorMarshalJSONFull(t *testing.T) {
 
 func BenchmarkNewError_Defaults(b *testing.B) {
 	packageState.reset()
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_ = New(nil, "")
@@ -410,6 +735,7 @@ func BenchmarkNewError_Defaults(b *testing.B) {
 func BenchmarkNewError_NoCaller(b *testing.B) {
 	packageState.reset()
 	packageState.config.SetCaptureCaller(false)
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_ = New(nil, "")
@@ -419,6 +745,7 @@ func BenchmarkNewError_NoCaller(b *testing.B) {
 func BenchmarkNewError_NoProcess(b *testing.B) {
 	packageState.reset()
 	packageState.config.SetCaptureProcess(false)
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_ = New(nil, "")
@@ -429,6 +756,7 @@ func BenchmarkNewError_NoCallerNoProcess(b *testing.B) {
 	packageState.reset()
 	packageState.config.SetCaptureCaller(false)
 	packageState.config.SetCaptureProcess(false)
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_ = New(nil, "")
@@ -437,8 +765,60 @@ func BenchmarkNewError_NoCallerNoProcess(b *testing.B) {
 
 func BenchmarkNewError_NoFeatures(b *testing.B) {
 	packageState.config.Set(false, false, false, true, false, true, 0, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = New(nil, "")
+	}
+}
+
+func BenchmarkNewError_Memory(b *testing.B) {
+	packageState.reset()
+	packageState.config.SetCaptureMemory(true)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = New(nil, "")
+	}
+}
+
+func BenchmarkNewError_NoMemoryNoFragments(b *testing.B) {
+	packageState.reset()
+	packageState.config.SetCaptureMemory(false)
+	packageState.config.SetCaptureSourceFragments(false)
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_ = New(nil, "")
 	}
 }
+
+func BenchmarkNewError_ResolveFrames(b *testing.B) {
+	packageState.reset()
+	packageState.config.SetCaptureProcess(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = New(nil, "").Caller.Frames()
+	}
+}
+
+func BenchmarkNewError_ResolveStackTrace(b *testing.B) {
+	packageState.reset()
+	packageState.config.SetCaptureProcess(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = New(nil, "").Caller.StackTrace()
+	}
+}
+
+func BenchmarkNewError_ResolveFragment(b *testing.B) {
+	packageState.reset()
+	packageState.config.SetCaptureProcess(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = New(nil, "").Caller.Fragment()
+	}
+}
@@ -0,0 +1,45 @@
+package wrappederror
+
+// ErrorCode types are machine-readable identifiers of errors, such as
+// "ERR_TIMEOUT" or "user.not_found".
+//
+// Give an error a code with the WithCode option.
+type ErrorCode string
+
+// ErrorKind types are machine-readable categories of errors, such as
+// "validation" or "network".
+//
+// Give an error a kind with the WithKind option.
+type ErrorKind string
+
+// Exported functions
+
+// HasCode returns whether or not any error in the error tree of err was created
+// by this package with the given code. It returns false if code is empty.
+func HasCode(err error, code ErrorCode) bool {
+	if code == "" {
+		return false
+	}
+
+	for _, e := range errorTree(err) {
+		if we, ok := asError(e); ok && we.Code() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Codes returns the codes of the errors in the error tree of err that were
+// created by this package, in pre-order. Errors without a code are skipped.
+//
+// Use it to find the first code in the tree that has a meaning, such as the
+// first code with a registered status.
+func Codes(err error) []ErrorCode {
+	var codes []ErrorCode
+	for _, e := range errorTree(err) {
+		if we, ok := asError(e); ok && we.Code() != "" {
+			codes = append(codes, we.Code())
+		}
+	}
+	return codes
+}
@@ -0,0 +1,69 @@
+package wrappederror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestHasCode(t *testing.T) {
+	e0 := NewWithOptions(nil, "error 0", WithCode("E0"))
+	e1 := New(e0, "error 1")
+	e2 := Join([]error{errors.New("error 2"), fmt.Errorf("wrapped: %w", e1)}, "joined")
+
+	t.Run("Has code 0", func(t *testing.T) {
+		testHasCode(t, e0, "E0", true)
+	})
+	t.Run("Has code 1", func(t *testing.T) {
+		testHasCode(t, e1, "E0", true)
+	})
+	t.Run("Has code 2", func(t *testing.T) {
+		testHasCode(t, e2, "E0", true)
+	})
+	t.Run("Has code 3", func(t *testing.T) {
+		testHasCode(t, e2, "E1", false)
+	})
+	t.Run("Has code 4", func(t *testing.T) {
+		testHasCode(t, errors.New("E0"), "E0", false)
+	})
+	t.Run("Has code 5", func(t *testing.T) {
+		testHasCode(t, nil, "E0", false)
+	})
+	t.Run("Has code 6", func(t *testing.T) {
+		testHasCode(t, New(nil, "test"), "", false)
+	})
+}
+
+func testHasCode(t *testing.T, err error, code ErrorCode, ex bool) {
+	if HasCode(err, code) != ex {
+		t.Errorf("Expected %t but received %t.\n", ex, HasCode(err, code))
+	}
+}
+
+func TestCodes(t *testing.T) {
+	e0 := NewWithOptions(nil, "error 0", WithCode("E0"))
+	e1 := NewWithOptions(e0, "error 1", WithCode("E1"))
+	e2 := Join([]error{New(errors.New("error 2"), "no code"), fmt.Errorf("wrapped: %w", e1)}, "joined")
+
+	t.Run("Codes 0", func(t *testing.T) {
+		testCodes(t, e0, []ErrorCode{"E0"})
+	})
+	t.Run("Codes 1", func(t *testing.T) {
+		testCodes(t, e1, []ErrorCode{"E1", "E0"})
+	})
+	t.Run("Codes 2", func(t *testing.T) {
+		testCodes(t, e2, []ErrorCode{"E1", "E0"})
+	})
+	t.Run("Codes 3", func(t *testing.T) {
+		testCodes(t, errors.New("E0"), nil)
+	})
+	t.Run("Codes 4", func(t *testing.T) {
+		testCodes(t, nil, nil)
+	})
+}
+
+func testCodes(t *testing.T, err error, ex []ErrorCode) {
+	if c := Codes(err); fmt.Sprint(c) != fmt.Sprint(ex) {
+		t.Errorf("Expected %v but received %v.\n", ex, c)
+	}
+}
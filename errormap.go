@@ -1,14 +1,92 @@
 package wrappederror
 
 import (
-	"fmt"
-	"hash/fnv"
+	"container/list"
+	"sort"
 	"sync"
+	"time"
 )
 
-// A hash map of errors.
+// The number of buckets that an error map's window is divided in to.
+const errorMapWindowBuckets = 16
+
+// SimilarErrorStat types describe the errors created with a fingerprint.
+type SimilarErrorStat struct {
+
+	// The errors' fingerprint.
+	Fingerprint string `json:"fingerprint"`
+
+	// The number of errors created with the fingerprint in the package's similar
+	// error window, or since the fingerprint was first seen if there is no
+	// window.
+	Count int `json:"count"`
+
+	// The number of errors created with the fingerprint since it was first seen.
+	Total int `json:"total"`
+
+	// The time that the first error with the fingerprint was created.
+	FirstSeen time.Time `json:"firstSeen"`
+
+	// The time that the last error with the fingerprint was created.
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// errorMapPolicy types limit the fingerprints stored by an error map and
+// determine how their errors are counted.
+type errorMapPolicy struct {
+
+	// The maximum number of fingerprints to store. Least recently seen
+	// fingerprints are evicted first. If capacity is less than 1, then the
+	// number of fingerprints isn't limited.
+	capacity int
+
+	// The duration after a fingerprint is last seen that it's evicted. If ttl
+	// is 0, then fingerprints don't expire.
+	ttl time.Duration
+
+	// The duration of the sliding window that errors are counted in. If window
+	// is 0, then errors are counted since their fingerprint was first seen.
+	window time.Duration
+}
+
+// A bounded map of error fingerprints to the errors created with them.
 type errorMap struct {
-	hashMap *sync.Map
+
+	// The map's entries keyed by fingerprint.
+	entries map[string]*list.Element
+
+	// The map's entries ordered from the most to the least recently seen.
+	order *list.List
+
+	// The function that returns the current time.
+	now func() time.Time
+
+	// Mutex for safe access to entries and order.
+	mutex *sync.Mutex
+}
+
+// errorMapEntry types store the errors created with a fingerprint.
+type errorMapEntry struct {
+	fingerprint string
+	total       int
+	firstSeen   time.Time
+	lastSeen    time.Time
+
+	// The width of each bucket in the window that buckets were counted in.
+	bucketWidth time.Duration
+
+	// The number of errors in each bucket of the sliding window.
+	buckets [errorMapWindowBuckets]errorMapBucket
+}
+
+// errorMapBucket types count the errors created during a period of time.
+type errorMapBucket struct {
+
+	// The index of the period since the Unix epoch.
+	index int64
+
+	// The number of errors created in the period.
+	count int
 }
 
 // Initializers
@@ -16,35 +94,162 @@ type errorMap struct {
 // newErrorMap creates and returns a new error map.
 func newErrorMap() *errorMap {
 	return &errorMap{
-		hashMap: new(sync.Map),
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+		mutex:   new(sync.Mutex),
 	}
 }
 
 // Non-exported methods
 
-// similarErrors returns the number of similar errors.
-func (m errorMap) similarErrors(err error) int {
-	hash := string(m.hashError(err))
-	if v, ok := m.hashMap.Load(hash); ok {
-		return v.(int)
+// addError adds an error with the fingerprint, fp, to the map and returns the
+// number of errors with the fingerprint that were counted by the policy, p,
+// before it was added.
+func (m *errorMap) addError(fp string, p errorMapPolicy) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := m.now()
+	m.evict(now, p)
+
+	var e *errorMapEntry
+	if el, ok := m.entries[fp]; ok {
+		e = el.Value.(*errorMapEntry)
+		m.order.MoveToFront(el)
+	} else {
+		e = &errorMapEntry{fingerprint: fp, firstSeen: now}
+		m.entries[fp] = m.order.PushFront(e)
+	}
+
+	similar := e.count(now, p.window)
+	e.add(now, p.window)
+
+	for p.capacity > 0 && m.order.Len() > p.capacity {
+		m.remove(m.order.Back())
+	}
+
+	return similar
+}
+
+// stats returns the statistics of the n fingerprints with the most errors
+// counted by the policy, p. If n is less than 1, then every fingerprint is
+// returned.
+func (m *errorMap) stats(n int, p errorMapPolicy) []SimilarErrorStat {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := m.now()
+	m.evict(now, p)
+
+	stats := make([]SimilarErrorStat, 0, m.order.Len())
+	for el := m.order.Front(); el != nil; el = el.Next() {
+		e := el.Value.(*errorMapEntry)
+		c := e.count(now, p.window)
+		if c == 0 {
+			continue
+		}
+
+		stats = append(stats, SimilarErrorStat{
+			Fingerprint: e.fingerprint,
+			Count:       c,
+			Total:       e.total,
+			FirstSeen:   e.firstSeen,
+			LastSeen:    e.lastSeen,
+		})
+	}
+
+	// The entries are ordered by when they were last seen, so a stable sort
+	// breaks ties with the most recently seen fingerprint.
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Count > stats[j].Count
+	})
+
+	if n > 0 && len(stats) > n {
+		stats = stats[:n]
 	}
-	return 0
+
+	return stats
 }
 
-// addError adds an error to the map.
-func (m *errorMap) addError(err error) {
-	hash := string(m.hashError(err))
-	if v, ok := m.hashMap.Load(hash); ok {
-		m.hashMap.Store(hash, v.(int)+1)
-	} else {
-		m.hashMap.Store(hash, 1)
+// evict removes the entries that haven't been seen within the policy's TTL.
+// The caller must hold the map's mutex.
+func (m *errorMap) evict(now time.Time, p errorMapPolicy) {
+	if p.ttl <= 0 {
+		return
+	}
+
+	for el := m.order.Back(); el != nil; el = m.order.Back() {
+		if now.Sub(el.Value.(*errorMapEntry).lastSeen) < p.ttl {
+			return
+		}
+		m.remove(el)
+	}
+}
+
+// remove removes the entry in the element, el. The caller must hold the map's
+// mutex.
+func (m *errorMap) remove(el *list.Element) {
+	delete(m.entries, el.Value.(*errorMapEntry).fingerprint)
+	m.order.Remove(el)
+}
+
+// count returns the number of errors in the window that ends at now, or the
+// total number of errors if window is 0.
+//
+// Errors are counted in buckets, so errors created up to one bucket width
+// before the window may be counted.
+func (e *errorMapEntry) count(now time.Time, window time.Duration) int {
+	if window <= 0 {
+		return e.total
+	}
+
+	width := errorMapBucketWidth(window)
+	if e.bucketWidth != width {
+		return 0
+	}
+
+	idx := now.UnixNano() / int64(width)
+	c := 0
+	for _, b := range e.buckets {
+		if b.index > idx-errorMapWindowBuckets && b.index <= idx {
+			c += b.count
+		}
+	}
+	return c
+}
+
+// add adds an error created at now to the entry.
+func (e *errorMapEntry) add(now time.Time, window time.Duration) {
+	e.total++
+	e.lastSeen = now
+
+	if window <= 0 {
+		return
+	}
+
+	width := errorMapBucketWidth(window)
+	if e.bucketWidth != width {
+		// The window changed, so the buckets can't be compared with the new ones.
+		e.bucketWidth = width
+		e.buckets = [errorMapWindowBuckets]errorMapBucket{}
 	}
+
+	idx := now.UnixNano() / int64(width)
+	b := &e.buckets[idx%errorMapWindowBuckets]
+	if b.index != idx {
+		b.index = idx
+		b.count = 0
+	}
+	b.count++
 }
 
-// hashError hashes an error.
-func (m errorMap) hashError(err error) []byte {
-	s := fmt.Sprintf("%+v", err)
-	h := fnv.New128a()
-	h.Write([]byte(s))
-	return h.Sum(nil)
+// Non-exported functions
+
+// errorMapBucketWidth returns the width of each bucket in the window.
+func errorMapBucketWidth(window time.Duration) time.Duration {
+	if w := window / errorMapWindowBuckets; w > 0 {
+		return w
+	}
+	return 1
 }
@@ -1,41 +1,227 @@
 package wrappederror
 
 import (
-	"errors"
+	"fmt"
 	"testing"
+	"time"
 )
 
+// testErrorMapClock is a clock that only moves when it's advanced.
+type testErrorMapClock struct {
+	t time.Time
+}
+
+func (c *testErrorMapClock) now() time.Time {
+	return c.t
+}
+
+func (c *testErrorMapClock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func newTestErrorMap() (*errorMap, *testErrorMapClock) {
+	c := &testErrorMapClock{t: time.Date(2021, 3, 7, 12, 0, 0, 0, time.UTC)}
+	m := newErrorMap()
+	m.now = c.now
+	return m, c
+}
+
 func TestNewErrorMap(t *testing.T) {
 	m := newErrorMap()
-	if m.hashMap == nil {
+	if m.entries == nil || m.order == nil || m.now == nil || m.mutex == nil {
 		t.Error("Found unexpected nil.")
 	}
 }
 
 func TestErrorMapSimilarErrors(t *testing.T) {
-	m := newErrorMap()
+	m, _ := newTestErrorMap()
+	p := errorMapPolicy{}
 
-	e1 := errors.New("test")
+	fp1 := fingerprintHash("test")
 	t.Run("Error map similar 0", func(t *testing.T) {
-		testErrorMapSimilarErrors(t, m, e1, 0)
+		testErrorMapSimilarErrors(t, m, fp1, p, 0)
 	})
-	m.addError(e1)
+	m.addError(fp1, p)
 	t.Run("Error map similar 1", func(t *testing.T) {
-		testErrorMapSimilarErrors(t, m, e1, 1)
+		testErrorMapSimilarErrors(t, m, fp1, p, 1)
 	})
-	m.addError(e1)
+	m.addError(fp1, p)
 	t.Run("Error map similar 2", func(t *testing.T) {
-		testErrorMapSimilarErrors(t, m, e1, 2)
+		testErrorMapSimilarErrors(t, m, fp1, p, 2)
 	})
 
-	e2 := errors.New("testt")
+	fp2 := fingerprintHash("testt")
 	t.Run("Error map similar 3", func(t *testing.T) {
-		testErrorMapSimilarErrors(t, m, e2, 0)
+		testErrorMapSimilarErrors(t, m, fp2, p, 0)
+	})
+
+	if s := m.addError(fp1, p); s != 2 {
+		t.Errorf("Expected 2 but received %d.\n", s)
+	}
+}
+
+func testErrorMapSimilarErrors(
+	t *testing.T,
+	m *errorMap,
+	fp string,
+	p errorMapPolicy,
+	i int,
+) {
+	s := 0
+	for _, stat := range m.stats(0, p) {
+		if stat.Fingerprint == fp {
+			s = stat.Count
+		}
+	}
+
+	if s != i {
+		t.Errorf("Expected %d but received %d.\n", i, s)
+	}
+}
+
+func TestErrorMapCapacity(t *testing.T) {
+	m, c := newTestErrorMap()
+	p := errorMapPolicy{capacity: 2}
+
+	m.addError("a", p)
+	c.advance(time.Second)
+	m.addError("b", p)
+	c.advance(time.Second)
+	m.addError("a", p)
+	c.advance(time.Second)
+	m.addError("c", p)
+
+	if m.order.Len() != 2 || len(m.entries) != 2 {
+		t.Fatalf("Expected 2 entries but received %d.\n", m.order.Len())
+	}
+
+	t.Run("Error map capacity 0", func(t *testing.T) {
+		testErrorMapSimilarErrors(t, m, "a", p, 2)
+	})
+	t.Run("Error map capacity 1", func(t *testing.T) {
+		testErrorMapSimilarErrors(t, m, "b", p, 0)
+	})
+	t.Run("Error map capacity 2", func(t *testing.T) {
+		testErrorMapSimilarErrors(t, m, "c", p, 1)
+	})
+}
+
+func TestErrorMapTTL(t *testing.T) {
+	m, c := newTestErrorMap()
+	p := errorMapPolicy{ttl: time.Minute}
+
+	m.addError("a", p)
+	c.advance(30 * time.Second)
+	m.addError("b", p)
+	c.advance(30 * time.Second)
+
+	t.Run("Error map TTL 0", func(t *testing.T) {
+		testErrorMapSimilarErrors(t, m, "a", p, 0)
+	})
+	t.Run("Error map TTL 1", func(t *testing.T) {
+		testErrorMapSimilarErrors(t, m, "b", p, 1)
+	})
+
+	if _, ok := m.entries["a"]; ok {
+		t.Error("Expected the expired entry to be removed.")
+	}
+}
+
+func TestErrorMapWindow(t *testing.T) {
+	m, c := newTestErrorMap()
+	p := errorMapPolicy{window: 16 * time.Minute}
+
+	for i := 0; i < 4; i++ {
+		m.addError("a", p)
+		c.advance(5 * time.Minute)
+	}
+
+	// The errors were created 20, 15, 10 and 5 minutes ago.
+	t.Run("Error map window 0", func(t *testing.T) {
+		testErrorMapSimilarErrors(t, m, "a", p, 3)
+	})
+
+	c.advance(10 * time.Minute)
+	t.Run("Error map window 1", func(t *testing.T) {
+		testErrorMapSimilarErrors(t, m, "a", p, 1)
+	})
+
+	c.advance(time.Hour)
+	t.Run("Error map window 2", func(t *testing.T) {
+		testErrorMapSimilarErrors(t, m, "a", p, 0)
+	})
+
+	if s := m.addError("a", p); s != 0 {
+		t.Errorf("Expected 0 but received %d.\n", s)
+	}
+
+	t.Run("Error map window 3", func(t *testing.T) {
+		testErrorMapSimilarErrors(t, m, "a", errorMapPolicy{}, 5)
+	})
+	t.Run("Error map window 4", func(t *testing.T) {
+		testErrorMapSimilarErrors(t, m, "a", errorMapPolicy{window: time.Minute}, 0)
 	})
 }
 
-func testErrorMapSimilarErrors(t *testing.T, m *errorMap, err error, i int) {
-	if m.similarErrors(err) != i {
-		t.Errorf("Expected %d but received %d.\n", i, m.similarErrors(err))
+func TestErrorMapStats(t *testing.T) {
+	m, c := newTestErrorMap()
+	p := errorMapPolicy{window: 16 * time.Minute}
+	start := c.t
+
+	for i, n := range []int{1, 3, 2, 3} {
+		for j := 0; j < n; j++ {
+			m.addError(fmt.Sprintf("fp%d", i), p)
+		}
+		c.advance(time.Minute)
+	}
+
+	stats := m.stats(0, p)
+	if len(stats) != 4 {
+		t.Fatalf("Expected 4 stats but received %d.\n", len(stats))
+	}
+
+	for i, e := range []string{"fp3", "fp1", "fp2", "fp0"} {
+		if stats[i].Fingerprint != e {
+			t.Errorf("Expected %s at %d but received %s.\n", e, i, stats[i].Fingerprint)
+		}
+	}
+
+	s := stats[1]
+	if s.Count != 3 || s.Total != 3 {
+		t.Errorf("Expected 3 but received %d and %d.\n", s.Count, s.Total)
+	}
+	if !s.FirstSeen.Equal(start.Add(time.Minute)) || !s.LastSeen.Equal(s.FirstSeen) {
+		t.Errorf("Unexpected times %s and %s.\n", s.FirstSeen, s.LastSeen)
+	}
+
+	if stats = m.stats(2, p); len(stats) != 2 {
+		t.Errorf("Expected 2 stats but received %d.\n", len(stats))
+	}
+
+	c.advance(time.Hour)
+	if stats = m.stats(0, p); len(stats) != 0 {
+		t.Errorf("Expected 0 stats but received %d.\n", len(stats))
+	}
+	if stats = m.stats(0, errorMapPolicy{}); len(stats) != 4 {
+		t.Errorf("Expected 4 stats but received %d.\n", len(stats))
+	}
+}
+
+func TestSimilarErrorStats(t *testing.T) {
+	ResetState()
+	defer ResetState()
+
+	for i := 0; i < 3; i++ {
+		_ = New(fmt.Errorf("stats error"), "stats")
+	}
+	_ = New(fmt.Errorf("other stats error"), "stats")
+
+	stats := SimilarErrorStats(1)
+	if len(stats) != 1 {
+		t.Fatalf("Expected 1 stat but received %d.\n", len(stats))
+	}
+
+	if stats[0].Count != 3 || stats[0].Fingerprint != fingerprintHash("stats error") {
+		t.Errorf("Unexpected stat %+v.\n", stats[0])
 	}
 }
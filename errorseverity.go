@@ -9,6 +9,9 @@ import (
 // ErrRegexRequired indicates that a regular expression is required.
 var ErrRegexRequired = errors.New("regex required")
 
+// ErrCodeRequired indicates that an error code is required.
+var ErrCodeRequired = errors.New("code required")
+
 // ErrorSeverityLevel types define an error severity level.
 type ErrorSeverityLevel string
 
@@ -21,19 +24,27 @@ const (
 	ErrorSeverityLevelSevere   ErrorSeverityLevel = "severe"
 )
 
-// ErrorSeverity types define an error severity with a title, level, and a
+// ErrorSeverity types define an error severity with a title, level, and either a
 // regular expression that is used to find matches in an error's `Error` method
-// output.
+// output, or an error code that is used to find errors with the same code.
 type ErrorSeverity struct {
 
 	// The severity's title.
 	Title string `json:"title"`
 
 	// The regular expression used to match against `Error` method strings.
+	//
+	// This value is nil if the severity matches errors by their code.
 	Regex *regexp.Regexp `json:"regex"`
 
 	// The severity level.
 	Level ErrorSeverityLevel `json:"level"`
+
+	// The error code used to match against errors' codes.
+	//
+	// This value is empty if the severity matches errors with its regular
+	// expression.
+	Code ErrorCode `json:"code,omitempty"`
 }
 
 // Initializers
@@ -62,6 +73,56 @@ func NewErrorSeverity(
 	}, nil
 }
 
+// NewErrorSeverityWithCode creates and returns a new error severity with the
+// given title, level and error code. The severity matches errors created by
+// this package with the same code instead of using a regular expression.
+//
+// If the code is empty, then this function will return an ErrCodeRequired
+// error.
+func NewErrorSeverityWithCode(
+	title string,
+	code ErrorCode,
+	level ErrorSeverityLevel,
+) (*ErrorSeverity, error) {
+	if len(code) == 0 {
+		return nil, ErrCodeRequired
+	}
+
+	return &ErrorSeverity{
+		Title: title,
+		Level: level,
+		Code:  code,
+	}, nil
+}
+
+// Exported methods
+
+// AtLeast returns whether or not the level is at least as severe as min.
+//
+// Levels are ordered from none, to low, moderate, high and severe. Unknown
+// levels are treated as none.
+func (l ErrorSeverityLevel) AtLeast(min ErrorSeverityLevel) bool {
+	return l.rank() >= min.rank()
+}
+
+// Non-exported methods
+
+// rank returns the level's position in the order of levels.
+func (l ErrorSeverityLevel) rank() int {
+	switch l {
+	case ErrorSeverityLevelLow:
+		return 1
+	case ErrorSeverityLevelModerate:
+		return 2
+	case ErrorSeverityLevelHigh:
+		return 3
+	case ErrorSeverityLevelSevere:
+		return 4
+	default:
+		return 0
+	}
+}
+
 // Stringer interface methods
 
 func (s ErrorSeverity) String() string {
@@ -71,7 +132,21 @@ func (s ErrorSeverity) String() string {
 // Non-exported methods
 
 // match matches the error against the error severity.
+//
+// Severities with a code match errors with the same code exactly, and don't
+// match any other errors.
 func (s ErrorSeverity) match(err error) float64 {
+	if s.Code != "" {
+		if we, ok := asError(err); ok && we.Code() == s.Code {
+			return 1.0
+		}
+		return 0.0
+	}
+
+	if s.Regex == nil {
+		return 0.0
+	}
+
 	es := err.Error()
 	if len(es) == 0 {
 		return 0.0
@@ -88,7 +163,17 @@ func (s ErrorSeverity) match(err error) float64 {
 }
 
 // equals returns whether or not the receiver is equal to severity. Two error
-// severities are considered equal if their regular expressesions are equal.
+// severities are considered equal if their codes and regular expressesions are
+// equal.
 func (s ErrorSeverity) equals(severity *ErrorSeverity) bool {
-	return s.Regex.String() == severity.Regex.String()
+	return s.Code == severity.Code && s.regexString() == severity.regexString()
+}
+
+// regexString returns the severity's regular expression as a string, or an
+// empty string if it doesn't have one.
+func (s ErrorSeverity) regexString() string {
+	if s.Regex == nil {
+		return ""
+	}
+	return s.Regex.String()
 }
@@ -49,6 +49,54 @@ func TestNewErrorSeverity(t *testing.T) {
 	})
 }
 
+func TestNewErrorSeverityWithCode(t *testing.T) {
+	es, err := NewErrorSeverityWithCode("code", "E1", ErrorSeverityLevelHigh)
+	if err != nil {
+		t.Fatalf("Expected a nil error but received %s.\n", err)
+	}
+	if es.Code != "E1" || es.Regex != nil {
+		t.Errorf("Unexpected error severity %+v.\n", es)
+	}
+
+	if _, err := NewErrorSeverityWithCode("code", "", ErrorSeverityLevelHigh); err != ErrCodeRequired {
+		t.Errorf("Expected %s but received %v.\n", ErrCodeRequired, err)
+	}
+}
+
+func TestErrorSeverityMatchCode(t *testing.T) {
+	es, _ := NewErrorSeverityWithCode("code", "E1", ErrorSeverityLevelHigh)
+
+	t.Run("Error severity match code 0", func(t *testing.T) {
+		testErrorSeverityMatch(t, es, NewWithOptions(nil, "E0", WithCode("E1")), 1.0)
+	})
+	t.Run("Error severity match code 1", func(t *testing.T) {
+		testErrorSeverityMatch(t, es, NewWithOptions(nil, "E1", WithCode("E0")), 0.0)
+	})
+	t.Run("Error severity match code 2", func(t *testing.T) {
+		testErrorSeverityMatch(t, es, errors.New("E1"), 0.0)
+	})
+
+	if es.equals(testErrorSeverities.es0) || testErrorSeverities.es0.equals(es) {
+		t.Error("Expected code and regex severities to not be equal.")
+	}
+}
+
+func TestErrorSeverityDetectCode(t *testing.T) {
+	es, _ := NewErrorSeverityWithCode("code", "DETECT", ErrorSeverityLevelSevere)
+	_ = RegisterErrorSeverity(es)
+	defer UnregisterErrorSeverity(es)
+
+	e := NewWithOptions(nil, "error", WithCode("DETECT"))
+	if e.Metadata.Severity != es {
+		t.Errorf("Expected %s but received %s.\n", es, e.Metadata.Severity)
+	}
+
+	e = New(e, "wrapped")
+	if e.Metadata.Severity != es {
+		t.Errorf("Expected %s but received %s.\n", es, e.Metadata.Severity)
+	}
+}
+
 func testNewErrorSeverity(
 	t *testing.T,
 	s, r string,
@@ -80,6 +128,35 @@ func TestErrorSeverityString(t *testing.T) {
 	}
 }
 
+func TestErrorSeverityLevelAtLeast(t *testing.T) {
+	t.Run("At least 0", func(t *testing.T) {
+		testErrorSeverityLevelAtLeast(t, ErrorSeverityLevelHigh, ErrorSeverityLevelModerate, true)
+	})
+	t.Run("At least 1", func(t *testing.T) {
+		testErrorSeverityLevelAtLeast(t, ErrorSeverityLevelModerate, ErrorSeverityLevelModerate, true)
+	})
+	t.Run("At least 2", func(t *testing.T) {
+		testErrorSeverityLevelAtLeast(t, ErrorSeverityLevelLow, ErrorSeverityLevelSevere, false)
+	})
+	t.Run("At least 3", func(t *testing.T) {
+		testErrorSeverityLevelAtLeast(t, ErrorSeverityLevelNone, ErrorSeverityLevelNone, true)
+	})
+	t.Run("At least 4", func(t *testing.T) {
+		testErrorSeverityLevelAtLeast(t, ErrorSeverityLevel("unknown"), ErrorSeverityLevelLow, false)
+	})
+}
+
+func testErrorSeverityLevelAtLeast(
+	t *testing.T,
+	l ErrorSeverityLevel,
+	min ErrorSeverityLevel,
+	e bool,
+) {
+	if r := l.AtLeast(min); r != e {
+		t.Errorf("Expected %t but received %t.\n", e, r)
+	}
+}
+
 func TestErrorSeverityMatch(t *testing.T) {
 	if err := RegisterErrorSeverity(testErrorSeverities.es0); err != nil {
 		t.Errorf("Unexpected error: %s\n", err)
@@ -0,0 +1,114 @@
+package wrappederror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The keys of the fields that annotate errors caused by a canceled context.
+const (
+	// FieldDeadline is the key of the context's deadline.
+	FieldDeadline = "deadline"
+
+	// FieldDeadlineOffset is the key of the duration between the context's
+	// deadline and when the error was created. The duration is negative if the
+	// error was created before the deadline.
+	FieldDeadlineOffset = "deadlineOffset"
+)
+
+// ContextExtractor types extract a request-scoped value from a context.
+//
+// Extractors return the value and true, or false if the context doesn't
+// contain a value. Register extractors with the configuration's
+// AddContextExtractor method.
+type ContextExtractor func(ctx context.Context) (interface{}, bool)
+
+// Exported functions
+
+// ContextValueExtractor returns an extractor that extracts the value in the
+// context with the given key.
+func ContextValueExtractor(key interface{}) ContextExtractor {
+	return func(ctx context.Context) (interface{}, bool) {
+		v := ctx.Value(key)
+		return v, v != nil
+	}
+}
+
+// DeadlineExtractor extracts the context's deadline.
+func DeadlineExtractor(ctx context.Context) (interface{}, bool) {
+	return ctx.Deadline()
+}
+
+// Non-exported functions
+
+// newFields returns the fields extracted from the context by the extractors.
+// If err was caused by the context being canceled or its deadline passing, and
+// the context has a deadline, then the fields contain the deadline and the
+// offset from it at now.
+//
+// If the context is nil or no fields are extracted, then nil is returned.
+func newFields(
+	ctx context.Context,
+	err error,
+	extractors map[string]ContextExtractor,
+	now time.Time,
+) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+
+	fields := make(map[string]interface{})
+	for k, x := range extractors {
+		if v, ok := x(ctx); ok {
+			fields[k] = v
+		}
+	}
+
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		if d, ok := ctx.Deadline(); ok {
+			fields[FieldDeadline] = d
+			fields[FieldDeadlineOffset] = now.Sub(d)
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return fields
+}
+
+// redactFields returns a copy of the fields with their values redacted.
+func redactFields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	r := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		r[k] = redactContext(v)
+	}
+	return r
+}
+
+// fieldsString returns the redacted fields as space separated key=value pairs
+// ordered by key.
+func fieldsString(fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%+v", k, redactContext(fields[k]))
+	}
+
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,266 @@
+package wrappederror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testFieldsKey string
+
+type testFieldsUser struct {
+	ID    int
+	Email string `we:"redact"`
+}
+
+func testFieldsContext() context.Context {
+	ctx := context.WithValue(context.Background(), testFieldsKey("request"), "req-1")
+	return context.WithValue(ctx, testFieldsKey("user"), testFieldsUser{ID: 7, Email: "a@b.c"})
+}
+
+func testFieldsExtractors() map[string]ContextExtractor {
+	return map[string]ContextExtractor{
+		"requestID": ContextValueExtractor(testFieldsKey("request")),
+		"user":      ContextValueExtractor(testFieldsKey("user")),
+		"missing":   ContextValueExtractor(testFieldsKey("missing")),
+	}
+}
+
+func TestNewFields(t *testing.T) {
+	now := time.Now()
+	x := testFieldsExtractors()
+
+	f := newFields(testFieldsContext(), nil, x, now)
+	if len(f) != 2 || f["requestID"] != "req-1" {
+		t.Errorf("Unexpected fields %v.\n", f)
+	}
+
+	if f := newFields(nil, nil, x, now); f != nil {
+		t.Errorf("Unexpected fields %v.\n", f)
+	}
+
+	if f := newFields(context.Background(), nil, x, now); f != nil {
+		t.Errorf("Unexpected fields %v.\n", f)
+	}
+}
+
+func TestNewFieldsDeadline(t *testing.T) {
+	d := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), d)
+	defer cancel()
+
+	t.Run("New fields deadline 0", func(t *testing.T) {
+		testNewFieldsDeadline(t, ctx, context.Canceled, d.Add(-time.Second), -time.Second)
+	})
+	t.Run("New fields deadline 1", func(t *testing.T) {
+		err := fmt.Errorf("query: %w", context.DeadlineExceeded)
+		testNewFieldsDeadline(t, ctx, err, d.Add(time.Second), time.Second)
+	})
+	t.Run("New fields deadline 2", func(t *testing.T) {
+		f := newFields(ctx, errors.New("other"), nil, d)
+		if f != nil {
+			t.Errorf("Unexpected fields %v.\n", f)
+		}
+	})
+	t.Run("New fields deadline 3", func(t *testing.T) {
+		f := newFields(context.Background(), context.Canceled, nil, d)
+		if f != nil {
+			t.Errorf("Unexpected fields %v.\n", f)
+		}
+	})
+}
+
+func testNewFieldsDeadline(
+	t *testing.T,
+	ctx context.Context,
+	err error,
+	now time.Time,
+	offset time.Duration,
+) {
+	f := newFields(ctx, err, nil, now)
+	d, _ := ctx.Deadline()
+
+	if f[FieldDeadline] != d {
+		t.Errorf("Expected %s but received %v.\n", d, f[FieldDeadline])
+	}
+
+	if f[FieldDeadlineOffset] != offset {
+		t.Errorf("Expected %s but received %v.\n", offset, f[FieldDeadlineOffset])
+	}
+}
+
+func TestFieldsString(t *testing.T) {
+	s := fieldsString(map[string]interface{}{
+		"b":    2,
+		"a":    "x",
+		"user": testFieldsUser{ID: 7, Email: "a@b.c"},
+	})
+
+	if e := "a=x b=2 user={ID:7 Email:[REDACTED]}"; s != e {
+		t.Errorf("Expected %s but received %s.\n", e, s)
+	}
+}
+
+func TestDeadlineExtractor(t *testing.T) {
+	if _, ok := DeadlineExtractor(context.Background()); ok {
+		t.Error("Unexpected deadline.")
+	}
+
+	d := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), d)
+	defer cancel()
+
+	if v, ok := DeadlineExtractor(ctx); !ok || v != d {
+		t.Errorf("Expected %s but received %v.\n", d, v)
+	}
+}
+
+func TestNewCtx(t *testing.T) {
+	for k, x := range testFieldsExtractors() {
+		Config().AddContextExtractor(k, x)
+		defer Config().RemoveContextExtractor(k)
+	}
+
+	ctx, cancel := context.WithTimeout(testFieldsContext(), time.Minute)
+	defer cancel()
+
+	e := NewCtx(ctx, context.Canceled, "query failed")
+	if len(e.Metadata.Fields) != 4 || e.Metadata.Fields["requestID"] != "req-1" {
+		t.Fatalf("Unexpected fields %v.\n", e.Metadata.Fields)
+	}
+
+	if e.Metadata.Fields[FieldDeadlineOffset].(time.Duration) >= 0 {
+		t.Errorf("Expected a negative offset but received %v.\n", e.Metadata.Fields[FieldDeadlineOffset])
+	}
+
+	if s := e.Trace(); !strings.Contains(s, "requestID=req-1") || strings.Contains(s, "a@b.c") {
+		t.Errorf("Unexpected trace: %s\n", s)
+	}
+
+	if s := New(e, "wrapped").Trace(); !strings.Contains(s, "requestID=req-1") {
+		t.Errorf("Unexpected trace: %s\n", s)
+	}
+
+	s := e.FormatString(string(ErrorFormatTokenFields))
+	if !strings.HasPrefix(s, "deadline=") || !strings.Contains(s, "user={ID:7 Email:[REDACTED]}") {
+		t.Errorf("Unexpected fields: %s\n", s)
+	}
+
+	s = e.FormatString(string(ErrorFormatTokenDeadlineOffset))
+	if !strings.HasPrefix(s, "-") || s == "-" {
+		t.Errorf("Unexpected deadline offset: %s\n", s)
+	}
+
+	e = New(nil, "no fields")
+	for _, token := range []ErrorFormatToken{
+		ErrorFormatTokenFields,
+		ErrorFormatTokenDeadlineOffset,
+	} {
+		if s := e.FormatString(string(token)); s != "-" {
+			t.Errorf("Expected - but received %s.\n", s)
+		}
+	}
+}
+
+func TestNewCtxJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testFieldsKey("user"), testFieldsUser{ID: 7, Email: "a@b.c"})
+	e := NewWithOptions(
+		errors.New("fields JSON"),
+		"failed",
+		WithContext(ctx),
+		WithFingerprint("fields JSON"),
+	)
+	if e.Metadata.Fields != nil {
+		t.Fatalf("Unexpected fields %v.\n", e.Metadata.Fields)
+	}
+
+	Config().AddContextExtractor("user", ContextValueExtractor(testFieldsKey("user")))
+	defer Config().RemoveContextExtractor("user")
+
+	e = NewWithOptions(errors.New("fields JSON"), "failed", WithContext(ctx))
+
+	for _, minimal := range []bool{true, false} {
+		Config().SetMarshalMinimalJSON(minimal)
+
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+
+		if bytes.Contains(data, []byte("a@b.c")) {
+			t.Errorf("Expected the field to be redacted in %s.\n", data)
+		}
+
+		var d Error
+		if err := json.Unmarshal(data, &d); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+
+		u, ok := d.Metadata.Fields["user"].(map[string]interface{})
+		if !ok || u["ID"] != float64(7) {
+			t.Errorf("Unexpected fields %v.\n", d.Metadata.Fields)
+		}
+	}
+
+	Config().SetMarshalMinimalJSON(configDefaultMarshalMinimalJSON)
+
+	b := new(bytes.Buffer)
+	slog.New(slog.NewTextHandler(b, nil)).Error("failed", "err", e)
+	if !strings.Contains(b.String(), `err.fields.user="{ID:7 Email:[REDACTED]}"`) {
+		t.Errorf("Unexpected log: %s\n", b.String())
+	}
+
+	b.Reset()
+	if err := e.Encode(b, EncoderNameText); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if !strings.Contains(b.String(), "fields:") {
+		t.Errorf("Expected fields in %s.\n", b.String())
+	}
+}
+
+func TestErrorFields(t *testing.T) {
+	Config().AddContextExtractor("requestID", ContextValueExtractor(testFieldsKey("request")))
+	defer Config().RemoveContextExtractor("requestID")
+
+	e := NewCtx(testFieldsContext(), errors.New("fields"), "failed")
+
+	f := e.Fields()
+	if len(f) != 1 || f["requestID"] != "req-1" {
+		t.Fatalf("Unexpected fields %v.\n", f)
+	}
+
+	f["requestID"] = "req-2"
+	if e.Fields()["requestID"] != "req-1" {
+		t.Error("Expected the error's fields to be unmodified.")
+	}
+
+	if f := New(nil, "no fields").Fields(); f != nil {
+		t.Errorf("Unexpected fields %v.\n", f)
+	}
+}
+
+func TestNewCtxComparable(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testFieldsKey("user"), testFieldsUser{ID: 7, Email: "a@b.c"})
+
+	Config().AddContextExtractor("user", ContextValueExtractor(testFieldsKey("user")))
+	defer Config().RemoveContextExtractor("user")
+
+	e := NewWithOptions(errors.New("fields comparable"), "failed", WithContext(ctx))
+
+	var a, b error = *e, *e
+	if a != b {
+		t.Errorf("Expected the errors to be equal.\n")
+	}
+
+	s := fmt.Sprintf("%#v", e)
+	if !strings.Contains(s, "Fields:map[string]interface {}") || strings.Contains(s, "a@b.c") {
+		t.Errorf("Unexpected Go syntax: %s\n", s)
+	}
+}
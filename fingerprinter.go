@@ -0,0 +1,158 @@
+package wrappederror
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+)
+
+// Fingerprinter types create the fingerprints that group similar errors.
+//
+// Errors with the same fingerprint are similar. An error's fingerprint is
+// stored in its metadata, and the number of similar errors created before it is
+// stored in its metadata's Similar property.
+type Fingerprinter interface {
+
+	// Fingerprint returns the fingerprint of the error, e, or an empty string if
+	// the error shouldn't be grouped with other errors.
+	//
+	// When an error is fingerprinted, its metadata contains every value except
+	// its fingerprint and similar error count. If errors are sampled, then they
+	// are fingerprinted before they capture their call information, unless the
+	// fingerprinter is a CallerFingerprinter.
+	Fingerprint(e Error) string
+}
+
+// FingerprinterFunc types are functions that implement the Fingerprinter
+// interface.
+type FingerprinterFunc func(e Error) string
+
+// Fingerprint calls f(e).
+func (f FingerprinterFunc) Fingerprint(e Error) string {
+	return f(e)
+}
+
+// MessageFingerprinter types fingerprint errors by the message of the error
+// that they wrap.
+//
+// This is the package's default fingerprinter. Errors that don't wrap an error
+// aren't fingerprinted.
+type MessageFingerprinter struct{}
+
+// Fingerprint returns the fingerprint of the error, e.
+func (f MessageFingerprinter) Fingerprint(e Error) string {
+	if e.inner == nil {
+		return ""
+	}
+	return fingerprintHash(e.inner.Error())
+}
+
+// NormalizedFingerprinter types fingerprint errors by the message of the error
+// that they wrap after replacing values that vary between occurrences of the
+// same error.
+//
+// UUIDs, hexadecimal values, IP addresses with their ports, and numbers are
+// replaced with placeholders, so "dial tcp 10.0.0.1:3000: i/o timeout" and
+// "dial tcp 10.0.0.2:3001: i/o timeout" have the same fingerprint. Errors that
+// don't wrap an error aren't fingerprinted.
+type NormalizedFingerprinter struct{}
+
+// Fingerprint returns the fingerprint of the error, e.
+func (f NormalizedFingerprinter) Fingerprint(e Error) string {
+	if e.inner == nil {
+		return ""
+	}
+	return fingerprintHash(normalizeMessage(e.inner.Error()))
+}
+
+// TypeFingerprinter types fingerprint errors by the types of the errors in the
+// error tree that they wrap.
+//
+// Errors that don't wrap an error aren't fingerprinted.
+type TypeFingerprinter struct{}
+
+// Fingerprint returns the fingerprint of the error, e.
+func (f TypeFingerprinter) Fingerprint(e Error) string {
+	if e.inner == nil {
+		return ""
+	}
+
+	tree := errorTree(e.inner)
+	types := make([]string, len(tree))
+	for i, err := range tree {
+		types[i] = fmt.Sprintf("%T", err)
+	}
+
+	return fingerprintHash(strings.Join(types, fingerprintDelimiter))
+}
+
+// CallerFingerprinter types fingerprint errors by the function, file and line
+// that created them.
+//
+// Errors that didn't capture their call information aren't fingerprinted.
+type CallerFingerprinter struct{}
+
+// Fingerprint returns the fingerprint of the error, e.
+func (f CallerFingerprinter) Fingerprint(e Error) string {
+	if e.Caller == nil {
+		return ""
+	}
+
+	return fingerprintHash(fmt.Sprintf(
+		"%s%s%s:%d",
+		e.Caller.Function,
+		fingerprintDelimiter,
+		e.Caller.File,
+		e.Caller.Line,
+	))
+}
+
+// The delimiter of the values that are hashed in to a fingerprint.
+const fingerprintDelimiter = "\x00"
+
+// Message normalization rules applied in order.
+var fingerprintNormalizations = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{
+		regexp.MustCompile(
+			`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+		),
+		"<uuid>",
+	},
+	{regexp.MustCompile(`\[[0-9a-fA-F:.]*:[0-9a-fA-F:.]*\](:\d+)?`), "<addr>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<addr>"},
+	{regexp.MustCompile(`\b0[xX][0-9a-fA-F]+\b`), "<hex>"},
+	{regexp.MustCompile(`\d+(\.\d+)?`), "<n>"},
+}
+
+// Non-exported functions
+
+// normalizeMessage replaces the values in the message, s, that vary between
+// occurrences of the same error with placeholders.
+func normalizeMessage(s string) string {
+	for _, n := range fingerprintNormalizations {
+		s = n.pattern.ReplaceAllLiteralString(s, n.replacement)
+	}
+	return s
+}
+
+// fingerprintsCaller returns whether or not the fingerprinter, f, creates
+// fingerprints from an error's call information.
+func fingerprintsCaller(f Fingerprinter) bool {
+	switch f.(type) {
+	case CallerFingerprinter, *CallerFingerprinter:
+		return true
+	}
+	return false
+}
+
+// fingerprintHash returns the hexadecimal hash of s.
+func fingerprintHash(s string) string {
+	h := fnv.New128a()
+	h.Write([]byte(s))
+	return hex.EncodeToString(h.Sum(nil))
+}
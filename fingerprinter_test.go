@@ -0,0 +1,202 @@
+package wrappederror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	t.Run("Normalize message 0", func(t *testing.T) {
+		testNormalizeMessage(t, "dial tcp 10.0.0.1:3000: i/o timeout", "dial tcp <addr>: i/o timeout")
+	})
+	t.Run("Normalize message 1", func(t *testing.T) {
+		testNormalizeMessage(t, "dial tcp [::1]:443: refused", "dial tcp <addr>: refused")
+	})
+	t.Run("Normalize message 2", func(t *testing.T) {
+		testNormalizeMessage(t, "request 0b7cbe5c-2f0b-4a2e-9b3e-1c2d3e4f5a6b failed", "request <uuid> failed")
+	})
+	t.Run("Normalize message 3", func(t *testing.T) {
+		testNormalizeMessage(t, "bad pointer 0xc000012345", "bad pointer <hex>")
+	})
+	t.Run("Normalize message 4", func(t *testing.T) {
+		testNormalizeMessage(t, "retry 3 of 5 after 1.5s", "retry <n> of <n> after <n>s")
+	})
+	t.Run("Normalize message 5", func(t *testing.T) {
+		testNormalizeMessage(t, "not found", "not found")
+	})
+}
+
+func testNormalizeMessage(t *testing.T, s string, e string) {
+	if n := normalizeMessage(s); n != e {
+		t.Errorf("Expected \"%s\" but received \"%s\".\n", e, n)
+	}
+}
+
+type testFingerprintError struct{}
+
+func (e testFingerprintError) Error() string {
+	return "fingerprint"
+}
+
+func TestFingerprinters(t *testing.T) {
+	e1 := Error{inner: errors.New("dial tcp 10.0.0.1:3000: i/o timeout")}
+	e2 := Error{inner: errors.New("dial tcp 10.0.0.2:3001: i/o timeout")}
+	e3 := Error{inner: fmt.Errorf("read: %w", io.EOF)}
+	e4 := Error{inner: fmt.Errorf("write: %w", os.ErrClosed)}
+	e5 := Error{inner: testFingerprintError{}}
+
+	t.Run("Fingerprinters 0", func(t *testing.T) {
+		testFingerprinters(t, MessageFingerprinter{}, e1, e2, false)
+	})
+	t.Run("Fingerprinters 1", func(t *testing.T) {
+		testFingerprinters(t, NormalizedFingerprinter{}, e1, e2, true)
+	})
+	t.Run("Fingerprinters 2", func(t *testing.T) {
+		testFingerprinters(t, TypeFingerprinter{}, e3, e4, true)
+	})
+	t.Run("Fingerprinters 3", func(t *testing.T) {
+		testFingerprinters(t, TypeFingerprinter{}, e3, e5, false)
+	})
+	t.Run("Fingerprinters 4", func(t *testing.T) {
+		testFingerprinters(
+			t,
+			CallerFingerprinter{},
+			Error{Caller: &Caller{Function: "f", File: "a.go", Line: 1}},
+			Error{Caller: &Caller{Function: "f", File: "a.go", Line: 1}, inner: io.EOF},
+			true,
+		)
+	})
+	t.Run("Fingerprinters 5", func(t *testing.T) {
+		testFingerprinters(
+			t,
+			CallerFingerprinter{},
+			Error{Caller: &Caller{Function: "f", File: "a.go", Line: 1}},
+			Error{Caller: &Caller{Function: "f", File: "a.go", Line: 2}},
+			false,
+		)
+	})
+	t.Run("Fingerprinters 6", func(t *testing.T) {
+		f := FingerprinterFunc(func(e Error) string {
+			return string(e.Code())
+		})
+		testFingerprinters(
+			t,
+			f,
+			Error{Metadata: &Metadata{Code: "A"}, inner: io.EOF},
+			Error{Metadata: &Metadata{Code: "A"}, inner: os.ErrClosed},
+			true,
+		)
+	})
+
+	for _, f := range []Fingerprinter{
+		MessageFingerprinter{},
+		NormalizedFingerprinter{},
+		TypeFingerprinter{},
+		CallerFingerprinter{},
+	} {
+		if fp := f.Fingerprint(Error{}); fp != "" {
+			t.Errorf("Expected no fingerprint from %T but received %s.\n", f, fp)
+		}
+	}
+}
+
+func testFingerprinters(
+	t *testing.T,
+	f Fingerprinter,
+	e1 Error,
+	e2 Error,
+	equal bool,
+) {
+	fp1 := f.Fingerprint(e1)
+	fp2 := f.Fingerprint(e2)
+
+	if fp1 == "" || fp2 == "" {
+		t.Fatalf("Expected fingerprints but received \"%s\" and \"%s\".\n", fp1, fp2)
+	}
+
+	if (fp1 == fp2) != equal {
+		t.Errorf("Expected equal %t but received %s and %s.\n", equal, fp1, fp2)
+	}
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	defer Config().SetFingerprinter(nil)
+	Config().SetFingerprinter(NormalizedFingerprinter{})
+
+	e1 := New(errors.New("request 9f1c4e2a-0b7c-4a2e-9b3e-1c2d3e4f5a6b timed out"), "get failed")
+	e2 := New(errors.New("request 0b7cbe5c-2f0b-4a2e-9b3e-1c2d3e4f5a6b timed out"), "get failed")
+
+	if e1.Metadata.Fingerprint == "" {
+		t.Fatal("Expected a fingerprint.")
+	}
+
+	if e1.Metadata.Fingerprint != e2.Metadata.Fingerprint {
+		t.Errorf("Expected %s but received %s.\n", e1.Metadata.Fingerprint, e2.Metadata.Fingerprint)
+	}
+
+	if e2.Metadata.Similar != e1.Metadata.Similar+1 {
+		t.Errorf("Expected %d but received %d.\n", e1.Metadata.Similar+1, e2.Metadata.Similar)
+	}
+
+	s := e1.FormatString(string(ErrorFormatTokenFingerprint))
+	if s != e1.Metadata.Fingerprint {
+		t.Errorf("Expected %s but received %s.\n", e1.Metadata.Fingerprint, s)
+	}
+
+	s = New(nil, "no fingerprint").FormatString(string(ErrorFormatTokenFingerprint))
+	if s != "-" {
+		t.Errorf("Expected - but received %s.\n", s)
+	}
+}
+
+func TestErrorFingerprintOptions(t *testing.T) {
+	err := errors.New("fingerprint options")
+
+	e := NewWithOptions(err, "a", WithFingerprint("group"))
+	if e.Metadata.Fingerprint != "group" {
+		t.Errorf("Expected group but received %s.\n", e.Metadata.Fingerprint)
+	}
+
+	e = NewWithOptions(err, "b", WithFingerprint("group"))
+	if e.Metadata.Similar != 1 {
+		t.Errorf("Expected 1 but received %d.\n", e.Metadata.Similar)
+	}
+
+	e = NewWithOptions(err, "c", WithFingerprinter(CallerFingerprinter{}))
+	if e.Metadata.Fingerprint != (CallerFingerprinter{}).Fingerprint(*e) {
+		t.Errorf("Unexpected fingerprint %s.\n", e.Metadata.Fingerprint)
+	}
+
+	e = NewWithOptions(err, "d", WithFingerprinter(nil))
+	if e.Metadata.Fingerprint != (MessageFingerprinter{}).Fingerprint(*e) {
+		t.Errorf("Unexpected fingerprint %s.\n", e.Metadata.Fingerprint)
+	}
+}
+
+func TestErrorFingerprintJSON(t *testing.T) {
+	e := New(errors.New("fingerprint JSON"), "failed")
+
+	for _, minimal := range []bool{true, false} {
+		Config().SetMarshalMinimalJSON(minimal)
+
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+
+		var d Error
+		if err := json.Unmarshal(data, &d); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+
+		if d.Metadata.Fingerprint != e.Metadata.Fingerprint {
+			t.Errorf("Expected %s but received %s.\n", e.Metadata.Fingerprint, d.Metadata.Fingerprint)
+		}
+	}
+
+	Config().SetMarshalMinimalJSON(configDefaultMarshalMinimalJSON)
+}
@@ -56,6 +56,9 @@ const (
 	// ErrorFormatTokenSimilar prints the error's similar error count.
 	ErrorFormatTokenSimilar ErrorFormatToken = "${{SIM}}"
 
+	// ErrorFormatTokenFingerprint prints the error's fingerprint.
+	ErrorFormatTokenFingerprint ErrorFormatToken = "${{FPR}}"
+
 	// ErrorFormatTokenRoutines prints the error's num routines.
 	ErrorFormatTokenRoutines ErrorFormatToken = "${{RTS}}"
 
@@ -73,6 +76,19 @@ const (
 
 	// ErrorFormatTokenSeverityLevel prints the error's severity level.
 	ErrorFormatTokenSeverityLevel ErrorFormatToken = "${{SEL}}"
+
+	// ErrorFormatTokenCode prints the error's code.
+	ErrorFormatTokenCode ErrorFormatToken = "${{COD}}"
+
+	// ErrorFormatTokenKind prints the error's kind.
+	ErrorFormatTokenKind ErrorFormatToken = "${{KND}}"
+
+	// ErrorFormatTokenFields prints the error's fields as key=value pairs.
+	ErrorFormatTokenFields ErrorFormatToken = "${{FLD}}"
+
+	// ErrorFormatTokenDeadlineOffset prints the offset of the error from the
+	// deadline of the context that it was created with.
+	ErrorFormatTokenDeadlineOffset ErrorFormatToken = "${{DLO}}"
 )
 
 const (
@@ -212,6 +228,8 @@ func (f formatter) newFormat(t string) (ErrorFormatToken, string) {
 		return ErrorFormatTokenIndex, "%d"
 	case ErrorFormatTokenSimilar:
 		return ErrorFormatTokenSimilar, "%d"
+	case ErrorFormatTokenFingerprint:
+		return ErrorFormatTokenFingerprint, "%s"
 	case ErrorFormatTokenRoutines:
 		return ErrorFormatTokenRoutines, "%d"
 	case ErrorFormatTokenCPUs:
@@ -224,6 +242,14 @@ func (f formatter) newFormat(t string) (ErrorFormatToken, string) {
 		return ErrorFormatTokenSeverityTitle, "%s"
 	case ErrorFormatTokenSeverityLevel:
 		return ErrorFormatTokenSeverityLevel, "%s"
+	case ErrorFormatTokenCode:
+		return ErrorFormatTokenCode, "%s"
+	case ErrorFormatTokenKind:
+		return ErrorFormatTokenKind, "%s"
+	case ErrorFormatTokenFields:
+		return ErrorFormatTokenFields, "%s"
+	case ErrorFormatTokenDeadlineOffset:
+		return ErrorFormatTokenDeadlineOffset, "%v"
 	default:
 		return errorFormatTokenNone, ""
 	}
@@ -233,7 +259,7 @@ func (f formatter) newFormat(t string) (ErrorFormatToken, string) {
 func (f formatter) value(e Error, t ErrorFormatToken) interface{} {
 	switch ErrorFormatToken(t) {
 	case ErrorFormatTokenContext:
-		return e.Context
+		return redactContext(e.context)
 	case ErrorFormatTokenInner:
 		return e.inner.Error()
 	case ErrorFormatTokenChain:
@@ -259,31 +285,31 @@ func (f formatter) value(e Error, t ErrorFormatToken) interface{} {
 		if e.Caller == nil {
 			return "-"
 		}
-		return e.Caller.StackTrace
+		return e.Caller.StackTrace()
 	case ErrorFormatTokenSourceLowerLine:
 		if e.Caller == nil {
 			return "-"
 		}
-		if e.Caller.Fragment == nil {
+		if e.Caller.Fragment() == nil {
 			return "-"
 		}
-		return e.Caller.Fragment.LowerLine
+		return e.Caller.Fragment().LowerLine
 	case ErrorFormatTokenSourceUpperLine:
 		if e.Caller == nil {
 			return "-"
 		}
-		if e.Caller.Fragment == nil {
+		if e.Caller.Fragment() == nil {
 			return "-"
 		}
-		return e.Caller.Fragment.UpperLine
+		return e.Caller.Fragment().UpperLine
 	case ErrorFormatTokenSource:
 		if e.Caller == nil {
 			return "-"
 		}
-		if e.Caller.Fragment == nil {
+		if e.Caller.Fragment() == nil {
 			return "-"
 		}
-		return e.Caller.Fragment.Source
+		return e.Caller.Fragment().Source
 	case ErrorFormatTokenTime:
 		return e.Metadata.Time
 	case ErrorFormatTokenDuration:
@@ -292,6 +318,11 @@ func (f formatter) value(e Error, t ErrorFormatToken) interface{} {
 		return e.Metadata.Index
 	case ErrorFormatTokenSimilar:
 		return e.Metadata.Similar
+	case ErrorFormatTokenFingerprint:
+		if e.Metadata.Fingerprint == "" {
+			return "-"
+		}
+		return e.Metadata.Fingerprint
 	case ErrorFormatTokenRoutines:
 		if e.Process == nil {
 			return processRoutinesNumberUnknown
@@ -322,6 +353,26 @@ func (f formatter) value(e Error, t ErrorFormatToken) interface{} {
 			return "-"
 		}
 		return e.Metadata.Severity.Level
+	case ErrorFormatTokenCode:
+		if e.Code() == "" {
+			return "-"
+		}
+		return e.Code()
+	case ErrorFormatTokenKind:
+		if e.Kind() == "" {
+			return "-"
+		}
+		return e.Kind()
+	case ErrorFormatTokenFields:
+		if len(e.fields()) == 0 {
+			return "-"
+		}
+		return fieldsString(e.fields())
+	case ErrorFormatTokenDeadlineOffset:
+		if v, ok := e.fields()[FieldDeadlineOffset]; ok {
+			return v
+		}
+		return "-"
 	default:
 		return nil
 	}
@@ -45,6 +45,24 @@ func TestFormatterFormat(t *testing.T) {
 	t.Run("Formatter format 5", func(t *testing.T) {
 		testFormatterFormat(t, testFormatter, *testErrors.e1, ef, ex)
 	})
+
+	ef = fmt.Sprintf("%s", ErrorFormatTokenContext)
+	ex = "error 1"
+	t.Run("Formatter format 6", func(t *testing.T) {
+		testFormatterFormat(t, testFormatter, *testErrors.e1, ef, ex)
+	})
+
+	ef = fmt.Sprintf("%s %s", ErrorFormatTokenCode, ErrorFormatTokenKind)
+	ex = "- -"
+	t.Run("Formatter format 7", func(t *testing.T) {
+		testFormatterFormat(t, testFormatter, *testErrors.e1, ef, ex)
+	})
+
+	e := NewWithOptions(nil, "error", WithCode("E1"), WithKind("network"))
+	ex = "E1 network"
+	t.Run("Formatter format 8", func(t *testing.T) {
+		testFormatterFormat(t, testFormatter, *e, ef, ex)
+	})
 }
 
 func testFormatterFormat(t *testing.T, f *formatter, e Error, ef, ex string) {
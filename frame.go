@@ -0,0 +1,82 @@
+package wrappederror
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Frame types contain information about a single stack frame.
+type Frame struct {
+
+	// The frame's fully qualified function name.
+	Function string `json:"function"`
+
+	// The import path of the package that contains the frame's function.
+	Package string `json:"package"`
+
+	// The full path of the frame's source file.
+	File string `json:"file"`
+
+	// The frame's line number.
+	Line int `json:"line"`
+
+	// The frame's program counter.
+	PC uintptr `json:"pc"`
+
+	// Whether or not the frame's function was inlined by the compiler.
+	Inlined bool `json:"inlined"`
+}
+
+// Initializers
+
+// newFrames resolves the program counters in pcs and returns their frames.
+func newFrames(pcs []uintptr) []Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	var frames []Frame
+	rfs := runtime.CallersFrames(pcs)
+	for {
+		rf, more := rfs.Next()
+		frames = append(frames, newFrame(rf))
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// newFrame creates and returns a new frame from a runtime frame.
+func newFrame(rf runtime.Frame) Frame {
+	return Frame{
+		Function: rf.Function,
+		Package:  functionPackage(rf.Function),
+		File:     rf.File,
+		Line:     rf.Line,
+		PC:       rf.PC,
+		Inlined:  rf.Func == nil && rf.Function != "",
+	}
+}
+
+// Stringer interface methods
+
+func (f Frame) String() string {
+	return fmt.Sprintf("%s\n\t%s:%d", f.Function, f.File, f.Line)
+}
+
+// Non-exported functions
+
+// functionPackage returns the package import path of the fully qualified
+// function name, fn.
+//
+// For example, the package of "github.com/a/b.(*T).M" is "github.com/a/b".
+func functionPackage(fn string) string {
+	s := strings.LastIndex(fn, "/")
+	if d := strings.Index(fn[s+1:], "."); d >= 0 {
+		return fn[:s+1+d]
+	}
+	return fn
+}
@@ -0,0 +1,47 @@
+package wrappederror
+
+import "testing"
+
+func TestFunctionPackage(t *testing.T) {
+	t.Run("Function package 0", func(t *testing.T) {
+		testFunctionPackage(t, "main.main", "main")
+	})
+	t.Run("Function package 1", func(t *testing.T) {
+		testFunctionPackage(t, "github.com/a/b.F", "github.com/a/b")
+	})
+	t.Run("Function package 2", func(t *testing.T) {
+		testFunctionPackage(t, "github.com/a/b.(*T).M", "github.com/a/b")
+	})
+	t.Run("Function package 3", func(t *testing.T) {
+		testFunctionPackage(t, "github.com/a/b.F.func1", "github.com/a/b")
+	})
+	t.Run("Function package 4", func(t *testing.T) {
+		testFunctionPackage(t, "", "")
+	})
+}
+
+func testFunctionPackage(t *testing.T, fn, ex string) {
+	if p := functionPackage(fn); p != ex {
+		t.Errorf("Expected \"%s\" but received \"%s\".\n", ex, p)
+	}
+}
+
+func TestNewFrames(t *testing.T) {
+	if newFrames(nil) != nil {
+		t.Error("Expected no frames.")
+	}
+
+	c := newCaller(1, false, 0)
+	f := c.Frames()[0]
+	if f.Function != "github.com/colinc86/wrappederror.TestNewFrames" {
+		t.Errorf("Unexpected function %s.\n", f.Function)
+	}
+}
+
+func TestFrameString(t *testing.T) {
+	// Sanity check
+	c := newCaller(1, false, 0)
+	if len(c.Frames()[0].String()) == 0 {
+		t.Error("Unexpected string length.")
+	}
+}
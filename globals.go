@@ -1,5 +1,10 @@
 package wrappederror
 
+import (
+	"context"
+	"runtime"
+)
+
 // The package's current state.
 //
 // Do not set this after launch.
@@ -17,6 +22,25 @@ func ResetState() {
 	packageState.reset()
 }
 
+// SimilarErrorStats returns the statistics of the n fingerprints with the most
+// similar errors, ordered by their count. If n is less than 1, then the
+// statistics of every tracked fingerprint are returned.
+//
+// If the package has a similar error window, then errors are counted in the
+// window and fingerprints without errors in the window are omitted.
+func SimilarErrorStats(n int) []SimilarErrorStat {
+	return packageState.getSimilarErrorStats(n)
+}
+
+// Redact returns the string, s, with the matches of the package's redaction
+// patterns replaced with its redaction mask.
+//
+// Errors redact their own output, so use Redact for strings that are built from
+// errors that weren't created by the package.
+func Redact(s string) string {
+	return redactString(s)
+}
+
 // RegisterErrorSeverity registers the error severity with the package. If the
 // severity has already been registered, then a ErrSeverityAlreadyRegistered
 // error is returned.
@@ -29,3 +53,101 @@ func RegisterErrorSeverity(severity *ErrorSeverity) error {
 func UnregisterErrorSeverity(severity *ErrorSeverity) {
 	packageState.unregisterSeverity(severity)
 }
+
+// RegisterEncoder registers the encoder with the package under the given name.
+// If an encoder has already been registered with the name, then an
+// ErrEncoderAlreadyRegistered error is returned.
+//
+// The package's built-in encoders are registered with the names
+// EncoderNameJSON, EncoderNameLogfmt, EncoderNameText and EncoderNameCBOR.
+func RegisterEncoder(name string, encoder Encoder) error {
+	return packageState.registerEncoder(name, encoder)
+}
+
+// UnregisterEncoder unregisters the encoder with the given name from the
+// package. If no encoder was registered with the name, then this function does
+// nothing.
+func UnregisterEncoder(name string) {
+	packageState.unregisterEncoder(name)
+}
+
+// LookupEncoder returns the encoder registered with the given name and true, or
+// nil and false if one doesn't exist.
+func LookupEncoder(name string) (Encoder, bool) {
+	encoder := packageState.getEncoder(name)
+	return encoder, encoder != nil
+}
+
+// RegisterReporter registers the reporter with the package under the given
+// name. Every error created after the reporter is registered with a severity
+// level of at least min is queued for the reporter. Errors without a severity
+// have the level ErrorSeverityLevelNone, so use it as min to report every
+// error.
+//
+// Errors are delivered asynchronously, and are dropped if the reporter's queue
+// is full. The size of the queue is determined by the package's configuration
+// when the reporter is registered. Reporters should create their own errors
+// with the WithoutReporting option so that they don't report their own
+// failures.
+//
+// If a reporter has already been registered with the name, then an
+// ErrReporterAlreadyRegistered error is returned.
+func RegisterReporter(name string, reporter Reporter, min ErrorSeverityLevel) error {
+	return packageState.registerReporter(name, reporter, min)
+}
+
+// UnregisterReporter unregisters the reporter with the given name from the
+// package. Errors that have already been queued for the reporter are still
+// reported, and Flush waits for them. If no reporter was registered with the
+// name, then this function does nothing.
+func UnregisterReporter(name string) {
+	packageState.unregisterReporter(name)
+}
+
+// Flush blocks until the errors queued for the package's reporters have been
+// reported, or the context is done. If the context is done first, then its
+// error is returned.
+//
+// Call Flush before your process exits so that queued errors aren't lost.
+func Flush(ctx context.Context) error {
+	return packageState.flushReporters(ctx)
+}
+
+// ReporterStats returns the statistics of the reporter registered with the
+// given name and true, or an empty statistic and false if one doesn't exist.
+func ReporterStats(name string) (ReporterStat, bool) {
+	return packageState.getReporterStats(name)
+}
+
+// RegisterObserver registers the observer with the package under the given
+// name. The observer is called with every error created after it is
+// registered, from the goroutine that creates the error, before the error is
+// returned.
+//
+// If an observer has already been registered with the name, then an
+// ErrObserverAlreadyRegistered error is returned.
+func RegisterObserver(name string, observer Observer) error {
+	return packageState.registerObserver(name, observer)
+}
+
+// UnregisterObserver unregisters the observer with the given name from the
+// package. If no observer was registered with the name, then this function
+// does nothing.
+func UnregisterObserver(name string) {
+	packageState.unregisterObserver(name)
+}
+
+// Helper marks the calling function as a helper function. When errors capture
+// their call information, helper functions are skipped so that the error's
+// caller identifies the helper's caller instead.
+//
+// Helper may be called simultaneously from multiple goroutines.
+func Helper() {
+	var pc [1]uintptr
+	if runtime.Callers(2, pc[:]) == 0 {
+		return
+	}
+
+	f, _ := runtime.CallersFrames(pc[:]).Next()
+	packageState.registerHelper(f.Function)
+}
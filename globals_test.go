@@ -1,6 +1,10 @@
 package wrappederror
 
-import "testing"
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
 
 func TestGlobalsConfig(t *testing.T) {
 	// Sanity check
@@ -8,3 +12,62 @@ func TestGlobalsConfig(t *testing.T) {
 		t.Error("Unexpected nil config.")
 	}
 }
+
+func TestGlobalsHelper(t *testing.T) {
+	e := testGlobalsHelperWrap()
+	if e.Caller.Function != "github.com/colinc86/wrappederror.TestGlobalsHelper" {
+		t.Errorf("Unexpected caller %s.\n", e.Caller)
+	}
+
+	fs := e.Caller.Frames()
+	if len(fs) == 0 || fs[0].Function != e.Caller.Function {
+		t.Error("Expected helper frames to be skipped.")
+	}
+
+	if f := e.Caller.Fragment(); f == nil || !strings.Contains(
+		f.Source,
+		"testGlobalsHelperWrap()",
+	) {
+		t.Errorf("Unexpected source fragment %s.\n", f)
+	}
+}
+
+func TestGlobalsNestedHelpers(t *testing.T) {
+	e := testGlobalsHelperWrapWrap()
+	if e.Caller.Function != "github.com/colinc86/wrappederror.TestGlobalsNestedHelpers" {
+		t.Errorf("Unexpected caller %s.\n", e.Caller)
+	}
+}
+
+func TestGlobalsEncoders(t *testing.T) {
+	if err := RegisterEncoder("globals", TextEncoder{}); err != nil {
+		t.Errorf("Unexpected error: %s\n", err)
+	}
+	if _, ok := LookupEncoder("globals"); !ok {
+		t.Error("Expected an encoder.")
+	}
+
+	UnregisterEncoder("globals")
+	if _, ok := LookupEncoder("globals"); ok {
+		t.Error("Unexpected encoder.")
+	}
+}
+
+func testGlobalsHelperWrap() *Error {
+	Helper()
+	return New(nil, "helper")
+}
+
+func testGlobalsHelperWrapWrap() *Error {
+	Helper()
+	return testGlobalsHelperWrap()
+}
+
+func TestGlobalsRedact(t *testing.T) {
+	defer Config().SetRedactionPatterns()
+	Config().SetRedactionPatterns(regexp.MustCompile(`token=(\S+)`))
+
+	if s := Redact("token=abc"); s != "token=[REDACTED]" {
+		t.Errorf("Expected token=[REDACTED] but received %s.\n", s)
+	}
+}
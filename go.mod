@@ -1,3 +1,3 @@
 module github.com/colinc86/wrappederror
 
-go 1.16
+go 1.21
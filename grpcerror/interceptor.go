@@ -0,0 +1,130 @@
+package grpcerror
+
+import (
+	"context"
+	"errors"
+
+	we "github.com/colinc86/wrappederror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Server interceptors
+
+// UnaryServerInterceptor returns a unary server interceptor that converts the
+// errors returned by handlers to statuses with the mapper.
+//
+// Errors that weren't created by package wrappederror are first wrapped with
+// the method's full name as their context.
+func (m *Mapper) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, m.serverError(err, info.FullMethod)
+	}
+}
+
+// StreamServerInterceptor returns a stream server interceptor that converts the
+// errors returned by handlers to statuses with the mapper.
+//
+// Errors that weren't created by package wrappederror are first wrapped with
+// the method's full name as their context.
+func (m *Mapper) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return m.serverError(handler(srv, ss), info.FullMethod)
+	}
+}
+
+// Client interceptors
+
+// UnaryClientInterceptor returns a unary client interceptor that reconstructs
+// errors from the statuses returned by servers.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return clientError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor returns a stream client interceptor that
+// reconstructs errors from the statuses returned by servers.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, clientError(err)
+		}
+		return &clientStream{ClientStream: cs}, nil
+	}
+}
+
+// clientStream types reconstruct errors from the statuses returned by a client
+// stream.
+type clientStream struct {
+	grpc.ClientStream
+}
+
+// RecvMsg receives a message and reconstructs its error.
+func (s *clientStream) RecvMsg(msg interface{}) error {
+	return clientError(s.ClientStream.RecvMsg(msg))
+}
+
+// SendMsg sends a message and reconstructs its error.
+func (s *clientStream) SendMsg(msg interface{}) error {
+	return clientError(s.ClientStream.SendMsg(msg))
+}
+
+// Non-exported methods
+
+// serverError converts the error returned by a handler to a status error.
+func (m *Mapper) serverError(err error, method string) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *we.Error
+	if !errors.As(err, &e) {
+		err = we.New(err, method)
+	}
+
+	return m.ToStatus(err).Err()
+}
+
+// Non-exported functions
+
+// clientError reconstructs an error from the status error, err. Errors that
+// aren't status errors, such as io.EOF, are returned unchanged.
+func clientError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return FromStatus(s)
+}
@@ -0,0 +1,137 @@
+package grpcerror
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	we "github.com/colinc86/wrappederror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testHealthServer is a health server whose methods return errors.
+type testHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	err error
+}
+
+func (s *testHealthServer) Check(
+	ctx context.Context,
+	req *healthpb.HealthCheckRequest,
+) (*healthpb.HealthCheckResponse, error) {
+	if req.GetService() == "" {
+		return &healthpb.HealthCheckResponse{
+			Status: healthpb.HealthCheckResponse_SERVING,
+		}, nil
+	}
+	return nil, s.err
+}
+
+func (s *testHealthServer) Watch(
+	req *healthpb.HealthCheckRequest,
+	stream healthpb.Health_WatchServer,
+) error {
+	if err := stream.Send(&healthpb.HealthCheckResponse{
+		Status: healthpb.HealthCheckResponse_SERVING,
+	}); err != nil {
+		return err
+	}
+	return s.err
+}
+
+func testHealthClient(t *testing.T, err error) healthpb.HealthClient {
+	m := testMapper()
+	lis := bufconn.Listen(1024 * 1024)
+
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(m.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(m.StreamServerInterceptor()),
+	)
+	healthpb.RegisterHealthServer(s, &testHealthServer{err: err})
+
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	cc, dialErr := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(StreamClientInterceptor()),
+	)
+	if dialErr != nil {
+		t.Fatalf("Error creating client: %s\n", dialErr)
+	}
+	t.Cleanup(func() { cc.Close() })
+
+	return healthpb.NewHealthClient(cc)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	e := we.NewWithOptions(errors.New("no rows"), "lookup", we.WithCode("NOT_FOUND"))
+	c := testHealthClient(t, e)
+
+	if _, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Unexpected error %s.\n", err)
+	}
+
+	_, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "db"})
+	testInterceptorError(t, err, e.Error(), codes.NotFound)
+
+	var re *we.Error
+	if !errors.As(err, &re) || re.Metadata.Index != e.Metadata.Index {
+		t.Errorf("Expected reconstructed error but received %v.\n", re)
+	}
+}
+
+func TestUnaryInterceptorsForeignError(t *testing.T) {
+	c := testHealthClient(t, errors.New("foreign"))
+
+	_, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "db"})
+	testInterceptorError(
+		t,
+		err,
+		healthpb.Health_Check_FullMethodName+": foreign",
+		codes.Internal,
+	)
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	e := we.NewWithOptions(nil, "watch failed", we.WithCode("NOT_FOUND"))
+	c := testHealthClient(t, e)
+
+	stream, err := c.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "db"})
+	if err != nil {
+		t.Fatalf("Unexpected error %s.\n", err)
+	}
+
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Unexpected error %s.\n", err)
+	}
+
+	_, err = stream.Recv()
+	testInterceptorError(t, err, e.Error(), codes.NotFound)
+}
+
+func testInterceptorError(t *testing.T, err error, message string, code codes.Code) {
+	var se *StatusError
+	if !errors.As(err, &se) {
+		t.Fatalf("Expected status error but received %T %v.\n", err, err)
+	}
+
+	if se.Error() != message {
+		t.Errorf("Expected %s but received %s.\n", message, se.Error())
+	}
+
+	if status.Code(err) != code {
+		t.Errorf("Expected %s but received %s.\n", code, status.Code(err))
+	}
+}
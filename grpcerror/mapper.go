@@ -0,0 +1,102 @@
+// Package grpcerror converts errors created by package wrappederror to and from
+// gRPC statuses, and provides interceptors that perform the conversions for
+// servers and clients.
+package grpcerror
+
+import (
+	"errors"
+	"sync"
+
+	we "github.com/colinc86/wrappederror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Mapper types map errors to gRPC status codes.
+//
+// A mapper looks for a status code in the following order, and uses the first
+// one it finds.
+//
+//  1. The status code registered with the code of an error in the error tree.
+//  2. The status code registered with the level of the error's severity.
+//  3. The status code of a gRPC status error in the error tree.
+//  4. The mapper's default status code.
+type Mapper struct {
+	codes       map[we.ErrorCode]codes.Code
+	levels      map[we.ErrorSeverityLevel]codes.Code
+	defaultCode codes.Code
+	mutex       *sync.RWMutex
+}
+
+// Initializers
+
+// NewMapper creates and returns a new mapper that maps errors without a
+// registered code, severity level or gRPC status to defaultCode.
+func NewMapper(defaultCode codes.Code) *Mapper {
+	return &Mapper{
+		codes:       make(map[we.ErrorCode]codes.Code),
+		levels:      make(map[we.ErrorSeverityLevel]codes.Code),
+		defaultCode: defaultCode,
+		mutex:       new(sync.RWMutex),
+	}
+}
+
+// Exported methods
+
+// RegisterCode maps errors with the given error code to the gRPC status code,
+// c.
+func (m *Mapper) RegisterCode(code we.ErrorCode, c codes.Code) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.codes[code] = c
+}
+
+// RegisterLevel maps errors with the given severity level to the gRPC status
+// code, c.
+func (m *Mapper) RegisterLevel(level we.ErrorSeverityLevel, c codes.Code) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.levels[level] = c
+}
+
+// Code returns the gRPC status code of the error.
+func (m *Mapper) Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	var e *we.Error
+	if errors.As(err, &e) {
+		if c, ok := m.registeredCode(e); ok {
+			return c
+		}
+	}
+
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+
+	return m.defaultCode
+}
+
+// Non-exported methods
+
+// registeredCode returns the status code registered with either the code of an
+// error in the error tree of e, or the level of e's severity.
+func (m *Mapper) registeredCode(e *we.Error) (codes.Code, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, code := range we.Codes(e) {
+		if c, ok := m.codes[code]; ok {
+			return c, true
+		}
+	}
+
+	if e.Metadata != nil && e.Metadata.Severity != nil {
+		c, ok := m.levels[e.Metadata.Severity.Level]
+		return c, ok
+	}
+
+	return 0, false
+}
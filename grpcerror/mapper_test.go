@@ -0,0 +1,50 @@
+package grpcerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	we "github.com/colinc86/wrappederror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func testMapper() *Mapper {
+	m := NewMapper(codes.Internal)
+	m.RegisterCode("NOT_FOUND", codes.NotFound)
+	m.RegisterLevel(we.ErrorSeverityLevelLow, codes.InvalidArgument)
+	return m
+}
+
+func TestMapperCode(t *testing.T) {
+	m := testMapper()
+	low, _ := we.NewErrorSeverity("Low", "low", we.ErrorSeverityLevelLow)
+	notFound := we.NewWithOptions(nil, "missing", we.WithCode("NOT_FOUND"))
+
+	t.Run("Mapper code 0", func(t *testing.T) {
+		testMapperCode(t, m, nil, codes.OK)
+	})
+	t.Run("Mapper code 1", func(t *testing.T) {
+		testMapperCode(t, m, errors.New("foreign"), codes.Internal)
+	})
+	t.Run("Mapper code 2", func(t *testing.T) {
+		testMapperCode(t, m, we.New(notFound, "lookup"), codes.NotFound)
+	})
+	t.Run("Mapper code 3", func(t *testing.T) {
+		testMapperCode(t, m, fmt.Errorf("handler: %w", notFound), codes.NotFound)
+	})
+	t.Run("Mapper code 4", func(t *testing.T) {
+		testMapperCode(t, m, we.NewWithOptions(nil, "bad", we.WithSeverity(low)), codes.InvalidArgument)
+	})
+	t.Run("Mapper code 5", func(t *testing.T) {
+		err := we.New(status.Error(codes.Unavailable, "down"), "dial")
+		testMapperCode(t, m, err, codes.Unavailable)
+	})
+}
+
+func testMapperCode(t *testing.T, m *Mapper, err error, ex codes.Code) {
+	if c := m.Code(err); c != ex {
+		t.Errorf("Expected %s but received %s.\n", ex, c)
+	}
+}
@@ -0,0 +1,160 @@
+package grpcerror
+
+import (
+	"errors"
+	"strconv"
+
+	we "github.com/colinc86/wrappederror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the domain of the ErrorInfo details added to statuses.
+const Domain = "github.com/colinc86/wrappederror"
+
+// The keys of the ErrorInfo details' metadata.
+const (
+	metadataKeyError    = "error"
+	metadataKeyKind     = "kind"
+	metadataKeyIndex    = "index"
+	metadataKeySimilar  = "similar"
+	metadataKeySeverity = "severity"
+)
+
+// The fields of errors that are carried by statuses.
+const statusFieldSet = we.JSONFieldSetMinimal | we.JSONFieldSeverity
+
+// StatusError types are errors reconstructed from gRPC statuses.
+//
+// They unwrap to the reconstructed error, and report the status they were
+// reconstructed from to the gRPC status package.
+type StatusError struct {
+
+	// The reconstructed error.
+	Err *we.Error
+
+	// The status the error was reconstructed from.
+	status *status.Status
+}
+
+// Exported methods
+
+// Unwrap returns the reconstructed error.
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// GRPCStatus returns the status the error was reconstructed from.
+func (e *StatusError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// Error interface methods
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+// Exported methods
+
+// ToStatus converts the error to a gRPC status with the mapper's status code.
+//
+// If the error tree contains an error created by package wrappederror, then the
+// status has ErrorInfo details with the error's code as its reason and the
+// error's JSON in its metadata, and DebugInfo details with the error's trace
+// and stack frames.
+func (m *Mapper) ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	s := status.New(m.Code(err), err.Error())
+
+	var e *we.Error
+	if !errors.As(err, &e) {
+		return s
+	}
+
+	ds, dErr := s.WithDetails(newErrorInfo(e), newDebugInfo(e))
+	if dErr != nil {
+		return s
+	}
+
+	return ds
+}
+
+// Exported functions
+
+// FromStatus converts a gRPC status to an error.
+//
+// If the status was created by ToStatus, then the returned error is a
+// *StatusError that contains the reconstructed error. Otherwise, the status's
+// error is returned. If the status is OK, then nil is returned.
+func FromStatus(s *status.Status) error {
+	if s == nil || s.Err() == nil {
+		return nil
+	}
+
+	for _, d := range s.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != Domain {
+			continue
+		}
+
+		data, ok := info.GetMetadata()[metadataKeyError]
+		if !ok {
+			continue
+		}
+
+		e := new(we.Error)
+		if err := e.UnmarshalJSON([]byte(data)); err != nil {
+			continue
+		}
+
+		return &StatusError{Err: e, status: s}
+	}
+
+	return s.Err()
+}
+
+// Non-exported functions
+
+// newErrorInfo creates ErrorInfo details from the error, e.
+func newErrorInfo(e *we.Error) *errdetails.ErrorInfo {
+	md := make(map[string]string)
+
+	if data, err := e.MarshalJSONWith(statusFieldSet); err == nil {
+		md[metadataKeyError] = string(data)
+	}
+
+	if e.Kind() != "" {
+		md[metadataKeyKind] = string(e.Kind())
+	}
+
+	if e.Metadata != nil {
+		md[metadataKeyIndex] = strconv.Itoa(e.Metadata.Index)
+		md[metadataKeySimilar] = strconv.Itoa(e.Metadata.Similar)
+		if e.Metadata.Severity != nil {
+			md[metadataKeySeverity] = string(e.Metadata.Severity.Level)
+		}
+	}
+
+	return &errdetails.ErrorInfo{
+		Reason:   string(e.Code()),
+		Domain:   Domain,
+		Metadata: md,
+	}
+}
+
+// newDebugInfo creates DebugInfo details from the error, e.
+func newDebugInfo(e *we.Error) *errdetails.DebugInfo {
+	d := &errdetails.DebugInfo{Detail: e.Trace()}
+
+	if e.Caller != nil {
+		for _, f := range e.Caller.Frames() {
+			d.StackEntries = append(d.StackEntries, f.String())
+		}
+	}
+
+	return d
+}
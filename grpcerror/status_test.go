@@ -0,0 +1,100 @@
+package grpcerror
+
+import (
+	"errors"
+	"testing"
+
+	we "github.com/colinc86/wrappederror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMapperToStatus(t *testing.T) {
+	m := testMapper()
+	e := we.NewWithOptions(
+		errors.New("no rows"),
+		"user lookup failed",
+		we.WithCode("NOT_FOUND"),
+		we.WithKind("database"),
+	)
+
+	s := m.ToStatus(e)
+	if s.Code() != codes.NotFound {
+		t.Errorf("Expected %s but received %s.\n", codes.NotFound, s.Code())
+	}
+	if s.Message() != e.Error() {
+		t.Errorf("Expected %s but received %s.\n", e.Error(), s.Message())
+	}
+
+	var info *errdetails.ErrorInfo
+	var debug *errdetails.DebugInfo
+	for _, d := range s.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.DebugInfo:
+			debug = d
+		}
+	}
+
+	if info == nil || debug == nil {
+		t.Fatalf("Unexpected details %v.\n", s.Details())
+	}
+
+	if info.GetReason() != "NOT_FOUND" ||
+		info.GetDomain() != Domain ||
+		info.GetMetadata()[metadataKeyKind] != "database" {
+		t.Errorf("Unexpected error info %v.\n", info)
+	}
+
+	if debug.GetDetail() != e.Trace() ||
+		len(debug.GetStackEntries()) != len(e.Caller.Frames()) {
+		t.Errorf("Unexpected debug info %v.\n", debug)
+	}
+
+	if m.ToStatus(nil) != nil {
+		t.Error("Expected nil.")
+	}
+
+	if s := m.ToStatus(errors.New("foreign")); len(s.Details()) != 0 {
+		t.Errorf("Unexpected details %v.\n", s.Details())
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	m := testMapper()
+	e := we.NewWithOptions(errors.New("no rows"), "lookup", we.WithCode("NOT_FOUND"))
+	s := m.ToStatus(e)
+
+	err := FromStatus(s)
+	var se *StatusError
+	if !errors.As(err, &se) {
+		t.Fatalf("Expected status error but received %T.\n", err)
+	}
+
+	if se.Error() != e.Error() || se.Err.Code() != e.Code() {
+		t.Errorf("Expected %s but received %s.\n", e, se)
+	}
+
+	if se.Err.Metadata.Index != e.Metadata.Index {
+		t.Errorf("Expected %d but received %d.\n", e.Metadata.Index, se.Err.Metadata.Index)
+	}
+
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected %s but received %s.\n", codes.NotFound, status.Code(err))
+	}
+
+	if !we.HasCode(err, "NOT_FOUND") {
+		t.Error("Expected code.")
+	}
+
+	if FromStatus(status.New(codes.OK, "")) != nil {
+		t.Error("Expected nil.")
+	}
+
+	plain := status.New(codes.Unavailable, "down")
+	if err := FromStatus(plain); status.Code(err) != codes.Unavailable || errors.As(err, &se) {
+		t.Errorf("Unexpected error %v.\n", err)
+	}
+}
@@ -0,0 +1,51 @@
+package wrappederror
+
+import "sync"
+
+// helperTable keeps track of functions that have been marked as helpers.
+type helperTable struct {
+	functions      map[string]struct{}
+	functionsMutex *sync.RWMutex
+}
+
+// Initializers
+
+// newHelperTable creates and returns a new helper table.
+func newHelperTable() *helperTable {
+	return &helperTable{
+		functions:      make(map[string]struct{}),
+		functionsMutex: new(sync.RWMutex),
+	}
+}
+
+// Methods
+
+// register marks the fully qualified function name, fn, as a helper.
+func (t *helperTable) register(fn string) {
+	t.functionsMutex.RLock()
+	_, ok := t.functions[fn]
+	t.functionsMutex.RUnlock()
+	if ok {
+		return
+	}
+
+	t.functionsMutex.Lock()
+	t.functions[fn] = struct{}{}
+	t.functionsMutex.Unlock()
+}
+
+// isHelper returns whether or not the fully qualified function name, fn, has
+// been marked as a helper.
+func (t *helperTable) isHelper(fn string) bool {
+	t.functionsMutex.RLock()
+	defer t.functionsMutex.RUnlock()
+	_, ok := t.functions[fn]
+	return ok
+}
+
+// isEmpty returns whether or not any functions have been marked as helpers.
+func (t *helperTable) isEmpty() bool {
+	t.functionsMutex.RLock()
+	defer t.functionsMutex.RUnlock()
+	return len(t.functions) == 0
+}
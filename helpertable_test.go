@@ -0,0 +1,26 @@
+package wrappederror
+
+import "testing"
+
+func TestHelperTableRegister(t *testing.T) {
+	ht := newHelperTable()
+	if !ht.isEmpty() {
+		t.Error("Expected an empty helper table.")
+	}
+
+	ht.register("a.b")
+	ht.register("a.b")
+
+	if ht.isEmpty() {
+		t.Error("Expected a non-empty helper table.")
+	}
+	if len(ht.functions) != 1 {
+		t.Errorf("Unexpected length %d.\n", len(ht.functions))
+	}
+	if !ht.isHelper("a.b") {
+		t.Error("Expected a helper.")
+	}
+	if ht.isHelper("a.c") {
+		t.Error("Unexpected helper.")
+	}
+}
@@ -1,10 +1,19 @@
 package wrappederror
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"time"
 )
 
+// ErrInvalidErrorJSON indicates that JSON data doesn't describe an error that
+// was marshaled by this package.
+var ErrInvalidErrorJSON = errors.New("invalid error JSON")
+
+// The key of the wrapped error in the JSON error types.
+const jsonKeyWraps = "wraps"
+
 // The generic JSON error type.
 type jsonError struct {
 	Error string      `json:"error"`
@@ -13,84 +22,359 @@ type jsonError struct {
 
 // The minimal JSON error type.
 type jsonWErrorMinimal struct {
-	Context  interface{}   `json:"context"`
-	Depth    int           `json:"depth"`
-	Time     time.Time     `json:"time"`
-	Duration time.Duration `json:"duration"`
-	Index    int           `json:"index"`
-	Similar  int           `json:"simlar,omitempty"`
-	File     string        `json:"file"`
-	Function string        `json:"function"`
-	Line     int           `json:"line"`
-	Inner    interface{}   `json:"wraps,omitempty"`
+	Context     interface{}            `json:"context"`
+	Fields      map[string]interface{} `json:"fields,omitempty"`
+	Depth       int                    `json:"depth"`
+	Time        time.Time              `json:"time"`
+	Duration    time.Duration          `json:"duration"`
+	Index       int                    `json:"index"`
+	Similar     int                    `json:"similar,omitempty"`
+	Fingerprint string                 `json:"fingerprint,omitempty"`
+	Sampled     bool                   `json:"sampled,omitempty"`
+	Code        ErrorCode              `json:"code,omitempty"`
+	Kind        ErrorKind              `json:"kind,omitempty"`
+	File        string                 `json:"file,omitempty"`
+	Function    string                 `json:"function,omitempty"`
+	Line        int                    `json:"line,omitempty"`
+	Inner       interface{}            `json:"wraps,omitempty"`
 }
 
 // The full JSON error type.
 type jsonWErrorFull struct {
-	Caller   *Caller     `json:"caller"`
-	Process  *Process    `json:"process"`
-	Metadata *Metadata   `json:"metadata"`
-	Context  interface{} `json:"context"`
-	Depth    int         `json:"depth"`
-	Inner    interface{} `json:"wraps"`
+	Caller   *jsonCaller            `json:"caller,omitempty"`
+	Process  *Process               `json:"process,omitempty"`
+	Metadata *jsonMetadata          `json:"metadata,omitempty"`
+	Context  interface{}            `json:"context"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	Depth    int                    `json:"depth"`
+	Inner    interface{}            `json:"wraps"`
+}
+
+// The JSON caller type.
+type jsonCaller struct {
+	File       string          `json:"file,omitempty"`
+	Function   string          `json:"function,omitempty"`
+	Line       int             `json:"line,omitempty"`
+	Frames     []Frame         `json:"frames,omitempty"`
+	StackTrace string          `json:"stackTrace,omitempty"`
+	Fragment   *SourceFragment `json:"sourceFragment,omitempty"`
+}
+
+// The JSON metadata type.
+type jsonMetadata struct {
+	Time        *time.Time     `json:"time,omitempty"`
+	Duration    *time.Duration `json:"duration,omitempty"`
+	Index       *int           `json:"index,omitempty"`
+	Similar     *int           `json:"similar,omitempty"`
+	Fingerprint string         `json:"fingerprint,omitempty"`
+	Sampled     bool           `json:"sampled,omitempty"`
+	Severity    *ErrorSeverity `json:"severity,omitempty"`
+	Code        ErrorCode      `json:"code,omitempty"`
+	Kind        ErrorKind      `json:"kind,omitempty"`
+}
+
+// The JSON type used to decode any of the JSON error types.
+type jsonAnyError struct {
+
+	// Generic error values.
+	Error *string `json:"error"`
+
+	// Minimal and full error values.
+	Context json.RawMessage        `json:"context"`
+	Fields  map[string]interface{} `json:"fields"`
+	Inner   json.RawMessage        `json:"wraps"`
+
+	// Minimal error values.
+	Time        time.Time     `json:"time"`
+	Duration    time.Duration `json:"duration"`
+	Index       int           `json:"index"`
+	Similar     int           `json:"similar"`
+	Fingerprint string        `json:"fingerprint"`
+	Sampled     bool          `json:"sampled"`
+	Code        ErrorCode     `json:"code"`
+	Kind        ErrorKind     `json:"kind"`
+	File        string        `json:"file"`
+	Function    string        `json:"function"`
+	Line        int           `json:"line"`
+
+	// Full error values.
+	Caller   *Caller   `json:"caller"`
+	Process  *Process  `json:"process"`
+	Metadata *Metadata `json:"metadata"`
 }
 
 // Initializers
 
+// newJSONCaller creates a new jsonCaller with the given fields.
+func newJSONCaller(c Caller, fields JSONFieldSet) *jsonCaller {
+	j := new(jsonCaller)
+
+	if fields.Has(JSONFieldCaller) {
+		j.File = c.File
+		j.Function = c.Function
+		j.Line = c.Line
+	}
+
+	if fields.Has(JSONFieldStack) {
+		j.Frames = c.Frames()
+		j.StackTrace = c.StackTrace()
+	}
+
+	if fields.Has(JSONFieldSource) {
+		j.Fragment = c.Fragment()
+	}
+
+	return j
+}
+
+// newJSONMetadata creates a new jsonMetadata with the given fields.
+func newJSONMetadata(m Metadata, fields JSONFieldSet) *jsonMetadata {
+	j := new(jsonMetadata)
+
+	if fields.Has(JSONFieldMetadata) {
+		j.Time = &m.Time
+		j.Duration = &m.Duration
+		j.Index = &m.Index
+		j.Similar = &m.Similar
+		j.Fingerprint = m.Fingerprint
+		j.Sampled = m.Sampled
+	}
+
+	if fields.Has(JSONFieldSeverity) {
+		j.Severity = m.Severity
+	}
+
+	if fields.Has(JSONFieldCode) {
+		j.Code = m.Code
+		j.Kind = m.Kind
+	}
+
+	return j
+}
+
 // newJSONErrorOrWError examines an error for its type and either initializes
-// a new jsonError or jsonWError.
-func newJSONErrorOrWError(err error) interface{} {
+// a new jsonError or jsonWError. Errors defined by this package are minimal
+// if minimal is true, and contain the given fields otherwise.
+func newJSONErrorOrWError(
+	err error,
+	fields JSONFieldSet,
+	minimal bool,
+) interface{} {
 	if err == nil {
 		return nil
 	}
 
 	if we, ok := err.(*Error); ok {
-		return newJSONWError(*we)
+		return newJSONWError(*we, fields, minimal)
+	}
+
+	if u, ok := err.(*multiError); ok {
+		return newJSONErrors(u.errs, fields, minimal)
 	}
 
-	return newJSONError(err)
+	return newJSONError(err, fields, minimal)
 }
 
-// newJSONError creates a new jsonError.
-func newJSONError(err error) *jsonError {
-	return &jsonError{
-		Error: err.Error(),
-		Inner: newJSONErrorOrWError(errors.Unwrap(err)),
+// newJSONErrors creates a new slice of JSON errors from errs.
+func newJSONErrors(
+	errs []error,
+	fields JSONFieldSet,
+	minimal bool,
+) []interface{} {
+	j := make([]interface{}, len(errs))
+	for i, err := range errs {
+		j[i] = newJSONErrorOrWError(err, fields, minimal)
+	}
+	return j
+}
+
+// newJSONError creates a new jsonError. If the error wraps more than one error,
+// then its wrapped errors are marshaled in to an array.
+func newJSONError(err error, fields JSONFieldSet, minimal bool) *jsonError {
+	j := &jsonError{Error: err.Error()}
+
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		if errs := u.Unwrap(); len(errs) > 0 {
+			j.Inner = newJSONErrors(errs, fields, minimal)
+		}
+	} else {
+		j.Inner = newJSONErrorOrWError(errors.Unwrap(err), fields, minimal)
 	}
+
+	return j
 }
 
 // newJSONWError creates a new jsonWError.
-func newJSONWError(e Error) interface{} {
-	if packageState.config.MarshalMinimalJSON() {
+func newJSONWError(e Error, fields JSONFieldSet, minimal bool) interface{} {
+	if minimal {
 		return newJSONWErrorMinimal(e)
 	}
-	return newJSONWErrorFull(e)
+	return newJSONWErrorFull(e, fields)
 }
 
 // newJSONWErrorMinimal creates a new minimal json error.
 func newJSONWErrorMinimal(e Error) *jsonWErrorMinimal {
-	return &jsonWErrorMinimal{
-		Context:  e.context,
-		Depth:    int(e.Depth()),
-		Time:     e.Metadata.Time,
-		Duration: e.Metadata.Duration,
-		Index:    e.Metadata.Index,
-		Similar:  e.Metadata.Similar,
-		File:     e.Caller.File,
-		Function: e.Caller.Function,
-		Line:     e.Caller.Line,
-		Inner:    newJSONErrorOrWError(e.inner),
-	}
-}
-
-// newJSONWErrorFull creates a new full json error.
-func newJSONWErrorFull(e Error) *jsonWErrorFull {
-	return &jsonWErrorFull{
-		Caller:   e.Caller,
-		Process:  e.Process,
-		Metadata: e.Metadata,
-		Context:  e.context,
-		Depth:    int(e.Depth()),
-		Inner:    newJSONErrorOrWError(e.inner),
+	j := &jsonWErrorMinimal{
+		Context:     redactContext(e.context),
+		Fields:      redactFields(e.fields()),
+		Depth:       int(e.Depth()),
+		Time:        e.Metadata.Time,
+		Duration:    e.Metadata.Duration,
+		Index:       e.Metadata.Index,
+		Similar:     e.Metadata.Similar,
+		Fingerprint: e.Metadata.Fingerprint,
+		Sampled:     e.Metadata.Sampled,
+		Code:        e.Metadata.Code,
+		Kind:        e.Metadata.Kind,
+		Inner:       newJSONErrorOrWError(e.inner, JSONFieldSetMinimal, true),
+	}
+
+	if e.Caller != nil {
+		j.File = e.Caller.File
+		j.Function = e.Caller.Function
+		j.Line = e.Caller.Line
+	}
+
+	return j
+}
+
+// newJSONWErrorFull creates a new full json error with the given fields.
+func newJSONWErrorFull(e Error, fields JSONFieldSet) *jsonWErrorFull {
+	j := &jsonWErrorFull{
+		Context: redactContext(e.context),
+		Fields:  redactFields(e.fields()),
+		Depth:   int(e.Depth()),
+		Inner:   newJSONErrorOrWError(e.inner, fields, false),
+	}
+
+	if e.Caller != nil &&
+		fields&(JSONFieldCaller|JSONFieldStack|JSONFieldSource) != 0 {
+		j.Caller = newJSONCaller(*e.Caller, fields)
+	}
+
+	if e.Process != nil && fields.Has(JSONFieldProcess) {
+		p := *e.Process
+		if !fields.Has(JSONFieldMemory) {
+			p.Memory = nil
+		}
+		j.Process = &p
+	}
+
+	if e.Metadata != nil &&
+		fields&(JSONFieldMetadata|JSONFieldSeverity|JSONFieldCode) != 0 {
+		j.Metadata = newJSONMetadata(*e.Metadata, fields)
+	}
+
+	return j
+}
+
+// Decoders
+
+// decodeJSONErrorOrWError decodes JSON data created from a value returned by
+// newJSONErrorOrWError.
+//
+// Errors marshaled by this package are decoded as *Error types, and all other
+// errors are decoded as *RemoteError types. Arrays of errors are decoded as
+// multi-errors.
+func decodeJSONErrorOrWError(data []byte) (error, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		return decodeJSONErrors(data)
+	}
+
+	var j jsonAnyError
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+
+	if j.Context != nil {
+		e := new(Error)
+		if err := decodeJSONWError(j, e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+
+	if j.Error != nil {
+		inner, err := decodeJSONErrorOrWError(j.Inner)
+		if err != nil {
+			return nil, err
+		}
+		return &RemoteError{Message: *j.Error, inner: inner}, nil
 	}
+
+	return nil, ErrInvalidErrorJSON
+}
+
+// decodeJSONErrors decodes a JSON array of errors in to a multi-error.
+func decodeJSONErrors(data []byte) (error, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, 0, len(raw))
+	for _, r := range raw {
+		err, decodeErr := decodeJSONErrorOrWError(r)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		errs = append(errs, err)
+	}
+
+	return newMultiError(errs), nil
+}
+
+// decodeJSONWError decodes either a minimal or full JSON error in to e.
+func decodeJSONWError(j jsonAnyError, e *Error) error {
+	if j.Context == nil {
+		return ErrInvalidErrorJSON
+	}
+
+	var ctx interface{}
+	if err := json.Unmarshal(j.Context, &ctx); err != nil {
+		return err
+	}
+
+	inner, err := decodeJSONErrorOrWError(j.Inner)
+	if err != nil {
+		return err
+	}
+
+	*e = Error{
+		Caller:   j.Caller,
+		Process:  j.Process,
+		Metadata: j.Metadata,
+		context:  ctx,
+		inner:    inner,
+	}
+
+	if e.Metadata == nil {
+		// The minimal JSON error type flattens its caller and metadata.
+		if j.File != "" || j.Function != "" {
+			e.Caller = &Caller{
+				File:     j.File,
+				Function: j.Function,
+				Line:     j.Line,
+			}
+		}
+
+		e.Metadata = &Metadata{
+			Time:        j.Time,
+			Duration:    j.Duration,
+			Index:       j.Index,
+			Similar:     j.Similar,
+			Fingerprint: j.Fingerprint,
+			Sampled:     j.Sampled,
+			Code:        j.Code,
+			Kind:        j.Kind,
+		}
+	}
+
+	e.Metadata.Fields = j.Fields
+	return nil
 }
@@ -1,7 +1,9 @@
 package wrappederror
 
 import (
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -26,7 +28,7 @@ func TestNewJSONErrorOrWError(t *testing.T) {
 }
 
 func testNewJSONErrorOrWError(t *testing.T, err error) {
-	e := newJSONErrorOrWError(err)
+	e := newJSONErrorOrWError(err, JSONFieldSetMinimal, true)
 	if _, ok := err.(*Error); ok {
 		if _, ok := e.(*jsonWErrorMinimal); !ok {
 			t.Error("Unexpected JSON wError type.")
@@ -35,3 +37,238 @@ func testNewJSONErrorOrWError(t *testing.T, err error) {
 		t.Error("Unexpected JSON error type.")
 	}
 }
+
+func TestErrorUnmarshalJSONMinimal(t *testing.T) {
+	packageState.config.SetMarshalMinimalJSON(true)
+	e := New(New(errors.New("root"), "error 1"), "error 2")
+	d := testErrorUnmarshalJSON(t, e)
+
+	if d.Caller.String() != e.Caller.String() {
+		t.Errorf("Expected caller %s but received %s.\n", e.Caller, d.Caller)
+	}
+	if d.Process != nil {
+		t.Error("Unexpected process.")
+	}
+}
+
+func TestErrorUnmarshalJSONFull(t *testing.T) {
+	packageState.config.SetMarshalMinimalJSON(false)
+	defer packageState.config.SetMarshalMinimalJSON(true)
+
+	e := New(New(errors.New("root"), "error 1"), "error 2")
+	d := testErrorUnmarshalJSON(t, e)
+
+	if d.Caller.StackTrace() != e.Caller.StackTrace() {
+		t.Error("Unexpected stack trace.")
+	}
+	if len(d.Caller.Frames()) != len(e.Caller.Frames()) {
+		t.Errorf("Expected %d frames but received %d.\n", len(e.Caller.Frames()), len(d.Caller.Frames()))
+	}
+	if d.Caller.Fragment().Source != e.Caller.Fragment().Source {
+		t.Error("Unexpected source fragment.")
+	}
+	if d.Process.String() != e.Process.String() {
+		t.Errorf("Expected process %s but received %s.\n", e.Process, d.Process)
+	}
+	if !d.Metadata.Time.Equal(e.Metadata.Time) ||
+		d.Metadata.Duration != e.Metadata.Duration {
+		t.Errorf("Expected metadata %s but received %s.\n", e.Metadata, d.Metadata)
+	}
+}
+
+func testErrorUnmarshalJSON(t *testing.T, e *Error) *Error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("Error marshaling json: %s\n", err)
+	}
+
+	var d *Error
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatalf("Error unmarshaling json: %s\n", err)
+	}
+
+	if d.Error() != e.Error() {
+		t.Errorf("Expected \"%s\" but received \"%s\".\n", e, d)
+	}
+	if d.Depth() != e.Depth() {
+		t.Errorf("Expected depth %d but received %d.\n", e.Depth(), d.Depth())
+	}
+	if d.Metadata.Index != e.Metadata.Index {
+		t.Errorf("Expected index %d but received %d.\n", e.Metadata.Index, d.Metadata.Index)
+	}
+
+	inner, ok := d.Unwrap().(*Error)
+	if !ok {
+		t.Fatalf("Unexpected inner error type %T.\n", d.Unwrap())
+	}
+	if inner.Metadata.Index != e.Unwrap().(*Error).Metadata.Index {
+		t.Errorf("Unexpected inner index %d.\n", inner.Metadata.Index)
+	}
+
+	var re *RemoteError
+	if !errors.As(d, &re) || re.Message != "root" {
+		t.Errorf("Expected a remote error but received %v.\n", re)
+	}
+
+	redata, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Error marshaling json: %s\n", err)
+	}
+	if string(redata) != string(data) {
+		t.Errorf("Expected %s but received %s.\n", data, redata)
+	}
+
+	return d
+}
+
+func TestErrorUnmarshalJSONCode(t *testing.T) {
+	e := NewWithOptions(nil, "error", WithCode("E1"), WithKind("network"))
+
+	for _, minimal := range []bool{true, false} {
+		packageState.config.SetMarshalMinimalJSON(minimal)
+
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatalf("Error marshaling json: %s\n", err)
+		}
+
+		var d Error
+		if err := json.Unmarshal(data, &d); err != nil {
+			t.Fatalf("Error unmarshaling json: %s\n", err)
+		}
+
+		if d.Code() != e.Code() || d.Kind() != e.Kind() {
+			t.Errorf("Expected %s %s but received %s %s.\n", e.Code(), e.Kind(), d.Code(), d.Kind())
+		}
+	}
+
+	packageState.config.SetMarshalMinimalJSON(true)
+}
+
+func TestErrorUnmarshalJSONInvalid(t *testing.T) {
+	var e Error
+	if err := json.Unmarshal([]byte(`{"error":"test"}`), &e); err != ErrInvalidErrorJSON {
+		t.Errorf("Expected %s but received %v.\n", ErrInvalidErrorJSON, err)
+	}
+	if err := json.Unmarshal([]byte(`{"context":"test","wraps":{}}`), &e); err != ErrInvalidErrorJSON {
+		t.Errorf("Expected %s but received %v.\n", ErrInvalidErrorJSON, err)
+	}
+	if err := json.Unmarshal([]byte(`[]`), &e); err == nil {
+		t.Error("Expected error.")
+	}
+}
+
+func TestDecodeJSONErrorOrWError(t *testing.T) {
+	err, derr := decodeJSONErrorOrWError([]byte(`{"error":"b","wraps":{"error":"a"}}`))
+	if derr != nil {
+		t.Fatalf("Unexpected error: %s\n", derr)
+	}
+
+	re, ok := err.(*RemoteError)
+	if !ok {
+		t.Fatalf("Unexpected error type %T.\n", err)
+	}
+	if re.Error() != "b" || re.Unwrap().Error() != "a" {
+		t.Errorf("Unexpected remote error %s.\n", re)
+	}
+
+	if err, _ := decodeJSONErrorOrWError([]byte("null")); err != nil {
+		t.Errorf("Expected nil but received %s.\n", err)
+	}
+}
+
+func TestErrorMarshalJSONWith(t *testing.T) {
+	defer packageState.config.SetCaptureMemory(configDefaultCaptureMemory)
+	packageState.config.SetCaptureMemory(true)
+
+	s, _ := NewErrorSeverity("test", "test", ErrorSeverityLevelLow)
+	e := NewWithOptions(New(errors.New("root"), "error 1"), "error 2", WithSeverity(s))
+
+	t.Run("Marshal JSON with 0", func(t *testing.T) {
+		testErrorMarshalJSONWith(
+			t,
+			e,
+			JSONFieldSetNone,
+			nil,
+			[]string{"caller", "process", "metadata"},
+		)
+	})
+	t.Run("Marshal JSON with 1", func(t *testing.T) {
+		testErrorMarshalJSONWith(
+			t,
+			e,
+			JSONFieldCaller|JSONFieldStack|JSONFieldSeverity,
+			[]string{"file", "frames", "stackTrace", "severity"},
+			[]string{"process", "sourceFragment", "time", "index"},
+		)
+	})
+	t.Run("Marshal JSON with 2", func(t *testing.T) {
+		testErrorMarshalJSONWith(
+			t,
+			e,
+			JSONFieldProcess|JSONFieldMetadata,
+			[]string{"goroutines", "time", "index"},
+			[]string{"caller", "memory", "severity"},
+		)
+	})
+	t.Run("Marshal JSON with 3", func(t *testing.T) {
+		testErrorMarshalJSONWith(
+			t,
+			e,
+			JSONFieldSetFull,
+			[]string{"file", "frames", "sourceFragment", "memory", "severity"},
+			nil,
+		)
+	})
+}
+
+func testErrorMarshalJSONWith(
+	t *testing.T,
+	e *Error,
+	fields JSONFieldSet,
+	in []string,
+	out []string,
+) {
+	data, err := e.MarshalJSONWith(fields)
+	if err != nil {
+		t.Fatalf("Error marshaling json: %s\n", err)
+	}
+
+	s := string(data)
+	for _, k := range in {
+		if !strings.Contains(s, "\""+k+"\":") {
+			t.Errorf("Expected key %s in %s.\n", k, s)
+		}
+	}
+	for _, k := range out {
+		if strings.Contains(s, "\""+k+"\":") {
+			t.Errorf("Unexpected key %s in %s.\n", k, s)
+		}
+	}
+
+	var d *Error
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatalf("Error unmarshaling json: %s\n", err)
+	}
+	if d.Error() != e.Error() {
+		t.Errorf("Expected \"%s\" but received \"%s\".\n", e, d)
+	}
+}
+
+func TestErrorMarshalJSONFieldSet(t *testing.T) {
+	defer packageState.config.SetMarshalMinimalJSON(true)
+	defer packageState.config.SetJSONFieldSet(JSONFieldSetFull)
+
+	e := New(nil, "test")
+	packageState.config.SetJSONFieldSet(JSONFieldCaller)
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("Error marshaling json: %s\n", err)
+	}
+
+	ex, _ := e.MarshalJSONWith(JSONFieldCaller)
+	if string(data) != string(ex) {
+		t.Errorf("Expected %s but received %s.\n", ex, data)
+	}
+}
@@ -0,0 +1,67 @@
+package wrappederror
+
+// JSONFieldSet types select the fields that are included when errors are
+// marshaled in to full JSON objects.
+//
+// Combine fields with the bitwise OR operator. For example, the following field
+// set includes call information, stack frames and severities, but not memory
+// statistics.
+//
+//	JSONFieldCaller | JSONFieldStack | JSONFieldSeverity
+type JSONFieldSet uint
+
+// A group of JSON fields.
+const (
+	// JSONFieldCaller includes the caller's file, function and line.
+	JSONFieldCaller JSONFieldSet = 1 << iota
+
+	// JSONFieldStack includes the caller's stack frames and stack trace.
+	JSONFieldStack
+
+	// JSONFieldSource includes the caller's source fragment.
+	JSONFieldSource
+
+	// JSONFieldProcess includes the process's goroutines, CPUs and cgo calls.
+	JSONFieldProcess
+
+	// JSONFieldMemory includes the process's memory statistics.
+	JSONFieldMemory
+
+	// JSONFieldMetadata includes the metadata's time, duration, index and
+	// similar error count.
+	JSONFieldMetadata
+
+	// JSONFieldSeverity includes the metadata's severity.
+	JSONFieldSeverity
+
+	// JSONFieldCode includes the metadata's code and kind.
+	JSONFieldCode
+)
+
+// A group of JSON field sets.
+const (
+	// JSONFieldSetNone only includes the error's context, depth and wrapped
+	// errors.
+	JSONFieldSetNone JSONFieldSet = 0
+
+	// JSONFieldSetMinimal includes the same information as the minimal version
+	// of an error's JSON.
+	JSONFieldSetMinimal = JSONFieldCaller | JSONFieldMetadata | JSONFieldCode
+
+	// JSONFieldSetFull includes every field.
+	JSONFieldSetFull = JSONFieldCaller |
+		JSONFieldStack |
+		JSONFieldSource |
+		JSONFieldProcess |
+		JSONFieldMemory |
+		JSONFieldMetadata |
+		JSONFieldSeverity |
+		JSONFieldCode
+)
+
+// Exported methods
+
+// Has returns whether or not the receiver contains every field in fields.
+func (s JSONFieldSet) Has(fields JSONFieldSet) bool {
+	return s&fields == fields
+}
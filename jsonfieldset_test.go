@@ -0,0 +1,31 @@
+package wrappederror
+
+import "testing"
+
+func TestJSONFieldSetHas(t *testing.T) {
+	fs := JSONFieldCaller | JSONFieldSeverity
+	t.Run("JSON field set has 0", func(t *testing.T) {
+		testJSONFieldSetHas(t, fs, JSONFieldCaller, true)
+	})
+	t.Run("JSON field set has 1", func(t *testing.T) {
+		testJSONFieldSetHas(t, fs, JSONFieldSeverity, true)
+	})
+	t.Run("JSON field set has 2", func(t *testing.T) {
+		testJSONFieldSetHas(t, fs, JSONFieldMemory, false)
+	})
+	t.Run("JSON field set has 3", func(t *testing.T) {
+		testJSONFieldSetHas(t, fs, JSONFieldCaller|JSONFieldMemory, false)
+	})
+	t.Run("JSON field set has 4", func(t *testing.T) {
+		testJSONFieldSetHas(t, JSONFieldSetFull, JSONFieldSetMinimal, true)
+	})
+	t.Run("JSON field set has 5", func(t *testing.T) {
+		testJSONFieldSetHas(t, JSONFieldSetNone, JSONFieldSetNone, true)
+	})
+}
+
+func testJSONFieldSetHas(t *testing.T, fs, f JSONFieldSet, ex bool) {
+	if fs.Has(f) != ex {
+		t.Errorf("Expected %t but received %t.\n", ex, fs.Has(f))
+	}
+}
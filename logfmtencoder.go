@@ -0,0 +1,106 @@
+package wrappederror
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// LogfmtEncoder types encode errors as logfmt key-value pairs.
+//
+// Each error in the error chain is written on its own line beginning with the
+// receiver. Nested values are flattened in to dot-separated keys, so the
+// caller's line number is written as "caller.line=42".
+type LogfmtEncoder struct{}
+
+// Encode encodes the error, e, and writes the result to w.
+func (enc LogfmtEncoder) Encode(w io.Writer, e Error) error {
+	n, err := newErrorEncoderNode(e)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	enc.encodeError(&b, n)
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// Non-exported methods
+
+// encodeError writes a line for the error node, n, followed by lines for each
+// of the errors it wraps.
+func (enc LogfmtEncoder) encodeError(b *strings.Builder, n *encoderNode) {
+	var pairs []string
+	for _, f := range n.fields {
+		if f.key != jsonKeyWraps {
+			pairs = enc.appendPairs(pairs, logfmtKey(f.key), f.node)
+		}
+	}
+
+	b.WriteString(strings.Join(pairs, " "))
+	b.WriteString("\n")
+
+	if wraps := n.field(jsonKeyWraps); wraps != nil {
+		switch wraps.kind {
+		case encoderNodeKindObject:
+			enc.encodeError(b, wraps)
+		case encoderNodeKindArray:
+			for _, item := range wraps.items {
+				enc.encodeError(b, item)
+			}
+		}
+	}
+}
+
+// appendPairs appends the flattened key-value pairs of the node, n, with the
+// given key.
+func (enc LogfmtEncoder) appendPairs(
+	pairs []string,
+	key string,
+	n *encoderNode,
+) []string {
+	switch n.kind {
+	case encoderNodeKindObject:
+		for _, f := range n.fields {
+			pairs = enc.appendPairs(pairs, key+"."+logfmtKey(f.key), f.node)
+		}
+		return pairs
+	case encoderNodeKindArray:
+		for i, item := range n.items {
+			pairs = enc.appendPairs(pairs, key+"."+strconv.Itoa(i), item)
+		}
+		return pairs
+	default:
+		return append(pairs, key+"="+logfmtValue(n.scalarString()))
+	}
+}
+
+// Non-exported functions
+
+// logfmtKey replaces characters in the key, k, that aren't valid in logfmt
+// keys.
+func logfmtKey(k string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, k)
+}
+
+// logfmtValue quotes the value, v, if necessary.
+func logfmtValue(v string) string {
+	if v == "" {
+		return `""`
+	}
+
+	for _, r := range v {
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return strconv.Quote(v)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,91 @@
+package wrappederror
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	defer Config().SetMarshalMinimalJSON(configDefaultMarshalMinimalJSON)
+	defer Config().SetJSONFieldSet(configDefaultJSONFieldSet)
+	Config().SetJSONFieldSet(JSONFieldSetFull)
+
+	e := testEncoderError()
+
+	var b bytes.Buffer
+	if err := (LogfmtEncoder{}).Encode(&b, *e); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines but received %d.\n", len(lines))
+	}
+
+	ex := []string{
+		`context="error 2"`,
+		"depth=2",
+		fmt.Sprintf("metadata.index=%d", e.Metadata.Index),
+		fmt.Sprintf("caller.line=%d", e.Caller.Line),
+		"caller.frames.0.function=" + e.Caller.Function,
+	}
+	for _, s := range ex {
+		if !strings.Contains(lines[0], s) {
+			t.Errorf("Expected \"%s\" in \"%s\".\n", s, lines[0])
+		}
+	}
+
+	if !strings.Contains(lines[1], `context="error 1"`) {
+		t.Errorf("Unexpected line \"%s\".\n", lines[1])
+	}
+	if lines[2] != `error="root cause"` {
+		t.Errorf("Unexpected line \"%s\".\n", lines[2])
+	}
+	if strings.Contains(b.String(), "wraps") {
+		t.Error("Unexpected wraps key.")
+	}
+	if strings.Contains(lines[0], "process") {
+		t.Error("Unexpected process key.")
+	}
+}
+
+func TestLogfmtEncoderDefaultFields(t *testing.T) {
+	e := New(errors.New("root cause"), "error")
+
+	var b bytes.Buffer
+	if err := (LogfmtEncoder{}).Encode(&b, *e); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	if !strings.Contains(b.String(), fmt.Sprintf("caller.line=%d", e.Caller.Line)) {
+		t.Errorf("Expected the caller in \"%s\".\n", b.String())
+	}
+	for _, s := range []string{"caller.frames", "process", "memory"} {
+		if strings.Contains(b.String(), s) {
+			t.Errorf("Unexpected \"%s\" in \"%s\".\n", s, b.String())
+		}
+	}
+}
+
+func TestLogfmtKey(t *testing.T) {
+	if k := logfmtKey("a b=c\"d"); k != "a_b_c_d" {
+		t.Errorf("Unexpected key %s.\n", k)
+	}
+}
+
+func TestLogfmtValue(t *testing.T) {
+	t.Run("Logfmt value 0", func(t *testing.T) { testLogfmtValue(t, "", `""`) })
+	t.Run("Logfmt value 1", func(t *testing.T) { testLogfmtValue(t, "a", "a") })
+	t.Run("Logfmt value 2", func(t *testing.T) { testLogfmtValue(t, "a b", `"a b"`) })
+	t.Run("Logfmt value 3", func(t *testing.T) { testLogfmtValue(t, "a=b", `"a=b"`) })
+	t.Run("Logfmt value 4", func(t *testing.T) { testLogfmtValue(t, "a\nb", `"a\nb"`) })
+}
+
+func testLogfmtValue(t *testing.T, v, ex string) {
+	if o := logfmtValue(v); o != ex {
+		t.Errorf("Expected %s but received %s.\n", ex, o)
+	}
+}
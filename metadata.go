@@ -29,30 +29,99 @@ type Metadata struct {
 
 	// The number of similar errors when this error was created.
 	//
-	// A similar error is an error that has the same inner error. A hashmap is
-	// maintained of inner error `Error() string` value hashes.
+	// A similar error is an error that has the same fingerprint. A bounded map
+	// is maintained of the number of errors created with each fingerprint. If
+	// the package has a similar error window, then only similar errors created
+	// in the window are counted.
 	//
 	// To turn this off, use the `SetTrackSimilarErrors` function. When tracking
 	// is off, this method always returns 0.
 	Similar int `json:"similar"`
 
+	// The error's fingerprint, or an empty string if it doesn't have one.
+	//
+	// Fingerprints are created by the package's configured Fingerprinter, and
+	// errors with the same fingerprint are similar. Use the fingerprint to group
+	// occurrences of the same error in logs.
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Whether or not the error's call and process information were elided
+	// because too many similar errors were created.
+	//
+	// To sample errors, use the `SetSampleLimit` function.
+	Sampled bool `json:"sampled,omitempty"`
+
 	// The package automatically detects the severity of errors based on
 	// ErrorSeverity instances registered through the package's configuration.
 	Severity *ErrorSeverity `json:"severity,omitempty"`
+
+	// The error's code, or an empty string if it doesn't have one.
+	//
+	// To give an error a code, use the `WithCode` option.
+	Code ErrorCode `json:"code,omitempty"`
+
+	// The error's kind, or an empty string if it doesn't have one.
+	//
+	// To give an error a kind, use the `WithKind` option.
+	Kind ErrorKind `json:"kind,omitempty"`
+
+	// Request-scoped values extracted from the context.Context that the error
+	// was created with.
+	//
+	// Values are extracted by the extractors registered with the
+	// AddContextExtractor configuration method. Errors caused by a canceled
+	// context are also annotated with the context's deadline and the offset
+	// from it when the error was created.
+	//
+	// If the error wasn't created with a context.Context, or no values were
+	// extracted, then this property is nil. Fields are encoded with the error
+	// rather than its metadata so that they're redacted.
+	Fields map[string]interface{} `json:"-"`
 }
 
 // Initializers
 
-// newMetadata creates metadata that should be added to an error. The function
-// requires the error's inner error to find similar errors.
-func newMetadata(err error) *Metadata {
-	return &Metadata{
+// newMetadata creates metadata that should be added to the error, e, whose
+// metadata is nil. The function requires the error's inner error to detect its
+// severity.
+//
+// If the options, o, specify a severity, then it is used instead of the best
+// match severity. Otherwise, if the options specify a code, then a severity
+// registered with the code is preferred.
+//
+// The error is fingerprinted after the rest of its metadata is created, and the
+// fingerprint is used to find similar errors. If the options' sample limit has
+// been reached, then the metadata is marked as sampled.
+func newMetadata(e Error, o *options) *Metadata {
+	err := e.inner
+
+	severity := o.severity
+	if severity == nil && o.code != "" {
+		severity = packageState.getCodeSeverity(o.code)
+	}
+	if severity == nil {
+		severity = packageState.getBestMatchSeverity(err)
+	}
+
+	m := &Metadata{
 		Time:     time.Now(),
 		Duration: packageState.getDurationSinceLaunch(),
 		Index:    packageState.config.getAndIncrementNextErrorIndex(),
-		Similar:  packageState.getSimilarErrorCount(err),
-		Severity: packageState.getBestMatchSeverity(err),
+		Severity: severity,
+		Code:     o.code,
+		Kind:     o.kind,
+	}
+	m.Fields = newFields(o.ctx, err, o.contextExtractors, m.Time)
+
+	m.Fingerprint = o.fingerprint
+	if m.Fingerprint == "" && o.fingerprinter != nil {
+		e.Metadata = m
+		m.Fingerprint = o.fingerprinter.Fingerprint(e)
 	}
+
+	m.Similar = packageState.getSimilarErrorCount(m.Fingerprint)
+	m.Sampled = o.sampleLimit > 0 && m.Similar >= o.sampleLimit
+	return m
 }
 
 // Stringer interface methods
@@ -1,6 +1,7 @@
 package wrappederror
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
@@ -8,8 +9,8 @@ import (
 
 func TestCurrentMetadata(t *testing.T) {
 	packageState.config.SetNextErrorIndex(1)
-	m1 := newMetadata(nil)
-	m2 := newMetadata(nil)
+	m1 := newMetadata(Error{}, testMetadataOptions())
+	m2 := newMetadata(Error{}, testMetadataOptions())
 
 	if m1.Index != 1 {
 		t.Errorf("Expected starting index 1 but received: %d\n", m1.Index)
@@ -29,13 +30,13 @@ func TestSimilarMetadata(t *testing.T) {
 	e4 := errors.New("testerror")
 	e5 := errors.New("testerror")
 
-	_ = newMetadata(e1)
-	_ = newMetadata(e2)
-	m1 := newMetadata(e3)
-	_ = newMetadata(e4)
-	m2 := newMetadata(e5)
-	_ = newMetadata(nil)
-	m3 := newMetadata(nil)
+	_ = newMetadata(Error{inner: e1}, testMetadataOptions())
+	_ = newMetadata(Error{inner: e2}, testMetadataOptions())
+	m1 := newMetadata(Error{inner: e3}, testMetadataOptions())
+	_ = newMetadata(Error{inner: e4}, testMetadataOptions())
+	m2 := newMetadata(Error{inner: e5}, testMetadataOptions())
+	_ = newMetadata(Error{}, testMetadataOptions())
+	m3 := newMetadata(Error{}, testMetadataOptions())
 
 	if m1.Similar != 2 {
 		t.Errorf("Expected 2 similar errors but received %d.\n", m1.Similar)
@@ -49,3 +50,102 @@ func TestSimilarMetadata(t *testing.T) {
 		t.Errorf("Expected no similar errors but received %d.\n", m3.Similar)
 	}
 }
+
+func testMetadataOptions() *options {
+	return newOptions(packageState.config, nil)
+}
+
+func TestSampledMetadata(t *testing.T) {
+	defer Config().SetSampleLimit(configDefaultSampleLimit)
+	Config().SetSampleLimit(2)
+
+	err := errors.New("sampled error")
+	var es []*Error
+	for i := 0; i < 4; i++ {
+		es = append(es, New(err, "sampled"))
+	}
+
+	for i, e := range es {
+		sampled := i >= 2
+		if e.Metadata.Sampled != sampled {
+			t.Errorf("Expected sampled %t for error %d.\n", sampled, i)
+		}
+
+		if sampled && (e.Caller != nil || e.Process != nil) {
+			t.Errorf("Expected no caller or process for error %d.\n", i)
+		}
+
+		if !sampled && (e.Caller == nil || e.Process == nil) {
+			t.Errorf("Expected a caller and process for error %d.\n", i)
+		}
+	}
+
+	if e := NewWithOptions(err, "sampled", WithSampleLimit(0)); e.Metadata.Sampled {
+		t.Error("Expected the error not to be sampled.")
+	}
+
+	if e := New(errors.New("other sampled error"), "sampled"); e.Metadata.Sampled {
+		t.Error("Expected the error not to be sampled.")
+	}
+
+	if e := New(nil, "sampled"); e.Metadata.Sampled {
+		t.Error("Expected the error not to be sampled.")
+	}
+}
+
+func TestSampledMetadataCaller(t *testing.T) {
+	callers := 0
+	f := FingerprinterFunc(func(e Error) string {
+		if e.Caller != nil {
+			callers++
+		}
+		return "sampled caller"
+	})
+
+	for i := 0; i < 3; i++ {
+		NewWithOptions(nil, "sampled", WithFingerprinter(f), WithSampleLimit(1))
+	}
+
+	if callers != 0 {
+		t.Errorf("Expected %d but received %d.\n", 0, callers)
+	}
+
+	opts := []Option{WithFingerprinter(CallerFingerprinter{}), WithSampleLimit(1)}
+	var es []*Error
+	for i := 0; i < 2; i++ {
+		es = append(es, NewWithOptions(nil, "sampled", opts...))
+	}
+
+	if es[0].Metadata.Fingerprint == "" || !es[1].Metadata.Sampled || es[1].Caller != nil {
+		t.Errorf("Expected the second error to be sampled by its caller.")
+	}
+}
+
+func TestSampledMetadataJSON(t *testing.T) {
+	opts := []Option{WithFingerprint("sampled JSON"), WithSampleLimit(1)}
+	_ = NewWithOptions(errors.New("sampled JSON"), "sampled", opts...)
+	e := NewWithOptions(errors.New("sampled JSON"), "sampled", opts...)
+	if !e.Metadata.Sampled {
+		t.Fatal("Expected the error to be sampled.")
+	}
+
+	for _, minimal := range []bool{true, false} {
+		Config().SetMarshalMinimalJSON(minimal)
+
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+
+		var d Error
+		if err := json.Unmarshal(data, &d); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+
+		if !d.Metadata.Sampled {
+			t.Errorf("Expected the decoded error to be sampled from %s.\n", data)
+		}
+	}
+
+	Config().SetMarshalMinimalJSON(configDefaultMarshalMinimalJSON)
+}
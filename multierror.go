@@ -0,0 +1,114 @@
+package wrappederror
+
+import "strings"
+
+// Multi-error string constants.
+var (
+	multiErrorLeadingDelimiter  string = "["
+	multiErrorDelimiter         string = "; "
+	multiErrorTrailingDelimiter string = "]"
+)
+
+// multiError types hold the errors joined by an error created with Join.
+//
+// Errors defined by this package treat multi-errors transparently. That is, the
+// errors that a multi-error holds are the children of the error that wraps it.
+type multiError struct {
+	errs []error
+}
+
+// Initializers
+
+// newMultiError creates and returns a new multi-error with the non-nil errors
+// in errs. If every error is nil, then nil is returned.
+func newMultiError(errs []error) error {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+
+	if len(nonNil) == 0 {
+		return nil
+	}
+
+	return &multiError{errs: nonNil}
+}
+
+// Exported methods
+
+// Unwrap returns the joined errors.
+func (e *multiError) Unwrap() []error {
+	return e.errs
+}
+
+// Error interface methods
+
+func (e *multiError) Error() string {
+	s := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		s[i] = err.Error()
+	}
+
+	return multiErrorLeadingDelimiter +
+		strings.Join(s, multiErrorDelimiter) +
+		multiErrorTrailingDelimiter
+}
+
+// Non-exported functions
+
+// errorChildren returns the errors that err wraps. Errors that implement
+// Unwrap() []error can have more than one child, and multi-errors are replaced
+// by the errors they hold.
+func errorChildren(err error) []error {
+	var errs []error
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		errs = u.Unwrap()
+	case interface{ Unwrap() error }:
+		errs = []error{u.Unwrap()}
+	}
+
+	var children []error
+	for _, c := range errs {
+		if m, ok := c.(*multiError); ok {
+			children = append(children, m.errs...)
+		} else if c != nil {
+			children = append(children, c)
+		}
+	}
+
+	return children
+}
+
+// errorTree returns err followed by the errors in its tree in pre-order. If err
+// is a multi-error, then it isn't included.
+func errorTree(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	var tree []error
+	if _, ok := err.(*multiError); !ok {
+		tree = append(tree, err)
+	}
+
+	for _, c := range errorChildren(err) {
+		tree = append(tree, errorTree(c)...)
+	}
+
+	return tree
+}
+
+// errorHeight returns the number of errors in the longest path from err to an
+// error that doesn't wrap any other errors, not including err.
+func errorHeight(err error) int {
+	h := 0
+	for _, c := range errorChildren(err) {
+		if ch := errorHeight(c) + 1; ch > h {
+			h = ch
+		}
+	}
+	return h
+}
@@ -0,0 +1,110 @@
+package wrappederror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNewMultiError(t *testing.T) {
+	if newMultiError(nil) != nil {
+		t.Error("Expected nil.")
+	}
+
+	if newMultiError([]error{nil, nil}) != nil {
+		t.Error("Expected nil.")
+	}
+
+	e0 := errors.New("error 0")
+	m, ok := newMultiError([]error{nil, e0}).(*multiError)
+	if !ok {
+		t.Fatal("Expected multi-error.")
+	}
+
+	if len(m.Unwrap()) != 1 || m.Unwrap()[0] != e0 {
+		t.Error("Unexpected joined errors.")
+	}
+}
+
+func TestMultiErrorError(t *testing.T) {
+	m := newMultiError([]error{errors.New("a"), errors.New("b")})
+	if m.Error() != "[a; b]" {
+		t.Errorf("Expected %s but received %s.\n", "[a; b]", m.Error())
+	}
+}
+
+func TestErrorChildren(t *testing.T) {
+	a := errors.New("a")
+	b := errors.New("b")
+
+	t.Run("Error children 0", func(t *testing.T) {
+		testErrorChildren(t, a, nil)
+	})
+	t.Run("Error children 1", func(t *testing.T) {
+		testErrorChildren(t, fmt.Errorf("c: %w", a), []error{a})
+	})
+	t.Run("Error children 2", func(t *testing.T) {
+		testErrorChildren(t, errors.Join(a, b), []error{a, b})
+	})
+	t.Run("Error children 3", func(t *testing.T) {
+		testErrorChildren(t, Join([]error{a, b}, "c"), []error{a, b})
+	})
+	t.Run("Error children 4", func(t *testing.T) {
+		testErrorChildren(t, New(nil, "c"), nil)
+	})
+}
+
+func testErrorChildren(t *testing.T, err error, ex []error) {
+	c := errorChildren(err)
+	if len(c) != len(ex) {
+		t.Fatalf("Expected %d but received %d.\n", len(ex), len(c))
+	}
+
+	for i := range c {
+		if c[i] != ex[i] {
+			t.Errorf("Expected %v but received %v.\n", ex[i], c[i])
+		}
+	}
+}
+
+func TestErrorTree(t *testing.T) {
+	a := errors.New("a")
+	b := errors.New("b")
+	c := New(b, "c")
+	m := newMultiError([]error{a, c})
+
+	tree := errorTree(m)
+	ex := []error{a, c, b}
+	if len(tree) != len(ex) {
+		t.Fatalf("Expected %d but received %d.\n", len(ex), len(tree))
+	}
+
+	for i := range tree {
+		if tree[i] != ex[i] {
+			t.Errorf("Expected %v but received %v.\n", ex[i], tree[i])
+		}
+	}
+
+	if errorTree(nil) != nil {
+		t.Error("Expected nil.")
+	}
+}
+
+func TestErrorHeight(t *testing.T) {
+	a := errors.New("a")
+	t.Run("Error height 0", func(t *testing.T) {
+		testErrorHeight(t, a, 0)
+	})
+	t.Run("Error height 1", func(t *testing.T) {
+		testErrorHeight(t, New(a, "b"), 1)
+	})
+	t.Run("Error height 2", func(t *testing.T) {
+		testErrorHeight(t, Join([]error{a, New(a, "b")}, "c"), 2)
+	})
+}
+
+func testErrorHeight(t *testing.T, err error, h int) {
+	if errorHeight(err) != h {
+		t.Errorf("Expected %d but received %d.\n", h, errorHeight(err))
+	}
+}
@@ -0,0 +1,28 @@
+package wrappederror
+
+import "errors"
+
+// ErrObserverAlreadyRegistered indicates that an observer has already been
+// registered with a name.
+var ErrObserverAlreadyRegistered = errors.New("observer already registered")
+
+// Observer types observe the errors created by the package as they are
+// created.
+//
+// Observers are registered with the package using the RegisterObserver
+// function. Unlike reporters, observers are called synchronously by the
+// goroutine that creates the error, so every error is observed. Observers must
+// return quickly, be safe for concurrent use, and must not create errors.
+type Observer interface {
+
+	// Observe observes the error, e.
+	Observe(e Error)
+}
+
+// ObserverFunc types are functions that implement the Observer interface.
+type ObserverFunc func(e Error)
+
+// Observe calls f(e).
+func (f ObserverFunc) Observe(e Error) {
+	f(e)
+}
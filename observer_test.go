@@ -0,0 +1,44 @@
+package wrappederror
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestObserverFunc(t *testing.T) {
+	var observed Error
+	o := ObserverFunc(func(e Error) {
+		observed = e
+	})
+
+	o.Observe(Error{Metadata: &Metadata{Index: 7}})
+	if observed.Metadata == nil || observed.Metadata.Index != 7 {
+		t.Errorf("Unexpected observed error %+v.\n", observed)
+	}
+}
+
+func TestRegisterObserver(t *testing.T) {
+	defer UnregisterObserver("test")
+
+	var observed []int
+	o := ObserverFunc(func(e Error) {
+		observed = append(observed, e.Metadata.Index)
+	})
+	if err := RegisterObserver("test", o); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if err := RegisterObserver("test", o); err != ErrObserverAlreadyRegistered {
+		t.Errorf("Expected %s but received %v.\n", ErrObserverAlreadyRegistered, err)
+	}
+
+	e := New(errors.New("registered observer"), "failed")
+	if len(observed) != 1 || observed[0] != e.Metadata.Index {
+		t.Errorf("Expected error %d to be observed but received %v.\n", e.Metadata.Index, observed)
+	}
+
+	UnregisterObserver("test")
+	New(errors.New("unregistered observer"), "failed")
+	if len(observed) != 1 {
+		t.Errorf("Expected 1 but received %d.\n", len(observed))
+	}
+}
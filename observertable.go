@@ -0,0 +1,53 @@
+package wrappederror
+
+import "sync"
+
+// observerTable keeps track of named observers.
+type observerTable struct {
+	observers      map[string]Observer
+	observersMutex *sync.RWMutex
+}
+
+// Initializers
+
+// newObserverTable creates and returns a new, empty observer table.
+func newObserverTable() *observerTable {
+	return &observerTable{
+		observers:      make(map[string]Observer),
+		observersMutex: new(sync.RWMutex),
+	}
+}
+
+// Methods
+
+// register registers the observer with the given name. If an observer has
+// already been registered with the name, then it returns an
+// ErrObserverAlreadyRegistered error.
+func (t *observerTable) register(name string, observer Observer) error {
+	t.observersMutex.Lock()
+	defer t.observersMutex.Unlock()
+
+	if _, ok := t.observers[name]; ok {
+		return ErrObserverAlreadyRegistered
+	}
+
+	t.observers[name] = observer
+	return nil
+}
+
+// unregister unregisters the observer with the given name.
+func (t *observerTable) unregister(name string) {
+	t.observersMutex.Lock()
+	defer t.observersMutex.Unlock()
+	delete(t.observers, name)
+}
+
+// observe calls each observer in the table with the error, e.
+func (t *observerTable) observe(e Error) {
+	t.observersMutex.RLock()
+	defer t.observersMutex.RUnlock()
+
+	for _, o := range t.observers {
+		o.Observe(e)
+	}
+}
@@ -0,0 +1,36 @@
+package wrappederror
+
+import "testing"
+
+func TestObserverTableRegister(t *testing.T) {
+	ot := newObserverTable()
+
+	o := ObserverFunc(func(e Error) {})
+	if err := ot.register("test", o); err != nil {
+		t.Errorf("Unexpected error: %s\n", err)
+	}
+	if err := ot.register("test", o); err != ErrObserverAlreadyRegistered {
+		t.Errorf("Expected %s but received %v.\n", ErrObserverAlreadyRegistered, err)
+	}
+
+	ot.unregister("test")
+	if err := ot.register("test", o); err != nil {
+		t.Errorf("Unexpected error: %s\n", err)
+	}
+}
+
+func TestObserverTableObserve(t *testing.T) {
+	ot := newObserverTable()
+
+	var observed []int
+	for _, name := range []string{"a", "b"} {
+		ot.register(name, ObserverFunc(func(e Error) {
+			observed = append(observed, e.Metadata.Index)
+		}))
+	}
+
+	ot.observe(Error{Metadata: &Metadata{Index: 1}})
+	if len(observed) != 2 || observed[0] != 1 || observed[1] != 1 {
+		t.Errorf("Unexpected observed errors %v.\n", observed)
+	}
+}
@@ -0,0 +1,179 @@
+package wrappederror
+
+import "context"
+
+// Option types configure a single error created with NewWithOptions.
+//
+// Options override the package's configuration for the error being created
+// without modifying the configuration.
+type Option func(o *options)
+
+// options types contain the values used to create a single error.
+type options struct {
+	captureCaller          bool
+	captureProcess         bool
+	captureMemory          bool
+	captureSourceFragments bool
+	sourceFragmentRadius   int
+	severity               *ErrorSeverity
+	code                   ErrorCode
+	kind                   ErrorKind
+	fingerprinter          Fingerprinter
+	fingerprint            string
+	sampleLimit            int
+	ctx                    context.Context
+	contextExtractors      map[string]ContextExtractor
+	skip                   int
+	recovered              bool
+	report                 bool
+}
+
+// Initializers
+
+// newOptions creates and returns options from the configuration, c, with opts
+// applied in order.
+func newOptions(c *Configuration, opts []Option) *options {
+	o := &options{
+		captureCaller:          c.CaptureCaller(),
+		captureProcess:         c.CaptureProcess(),
+		captureMemory:          c.CaptureMemory(),
+		captureSourceFragments: c.CaptureSourceFragments(),
+		sourceFragmentRadius:   c.SourceFragmentRadius(),
+		fingerprinter:          c.Fingerprinter(),
+		sampleLimit:            c.SampleLimit(),
+		contextExtractors:      c.contextExtractors.get().(map[string]ContextExtractor),
+		report:                 true,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// Exported functions
+
+// WithContext extracts the error's fields from the context, ctx, with the
+// package's context extractors.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithoutCaller prevents the error from capturing its call information.
+func WithoutCaller() Option {
+	return func(o *options) {
+		o.captureCaller = false
+	}
+}
+
+// WithoutProcess prevents the error from capturing process information.
+func WithoutProcess() Option {
+	return func(o *options) {
+		o.captureProcess = false
+	}
+}
+
+// WithoutMemory prevents the error from capturing memory statistics with its
+// process information.
+func WithoutMemory() Option {
+	return func(o *options) {
+		o.captureMemory = false
+	}
+}
+
+// WithoutReporting doesn't queue the error for the package's reporters.
+//
+// Reporters should create their own errors with this option, including errors
+// created by goroutines that they start, so that they don't report their own
+// failures.
+func WithoutReporting() Option {
+	return func(o *options) {
+		o.report = false
+	}
+}
+
+// WithoutSourceFragment prevents the error's caller from capturing a source
+// fragment.
+func WithoutSourceFragment() Option {
+	return func(o *options) {
+		o.captureSourceFragments = false
+	}
+}
+
+// WithFragmentRadius sets the line radius of the source fragment captured by
+// the error's caller.
+func WithFragmentRadius(radius int) Option {
+	return func(o *options) {
+		o.captureSourceFragments = true
+		o.sourceFragmentRadius = radius
+	}
+}
+
+// WithSeverity sets the error's severity instead of detecting it from the
+// package's registered error severities.
+func WithSeverity(severity *ErrorSeverity) Option {
+	return func(o *options) {
+		o.severity = severity
+	}
+}
+
+// WithCode sets the error's code.
+//
+// If no severity is set with WithSeverity, then an error severity registered
+// with the same code is used before detecting one.
+func WithCode(code ErrorCode) Option {
+	return func(o *options) {
+		o.code = code
+	}
+}
+
+// WithKind sets the error's kind.
+func WithKind(kind ErrorKind) Option {
+	return func(o *options) {
+		o.kind = kind
+	}
+}
+
+// WithFingerprinter fingerprints the error with f instead of the package's
+// configured fingerprinter.
+func WithFingerprinter(f Fingerprinter) Option {
+	return func(o *options) {
+		if f != nil {
+			o.fingerprinter = f
+		}
+	}
+}
+
+// WithFingerprint sets the error's fingerprint instead of creating one with a
+// fingerprinter.
+func WithFingerprint(fp string) Option {
+	return func(o *options) {
+		o.fingerprint = fp
+	}
+}
+
+// WithSampleLimit sets the number of similar errors that capture their call and
+// process information before the error is sampled. If limit is less than 1,
+// then the error isn't sampled.
+func WithSampleLimit(limit int) Option {
+	return func(o *options) {
+		o.sampleLimit = limit
+	}
+}
+
+// WithSkip skips the given number of additional stack frames when capturing the
+// error's call information.
+//
+// A skip of 0 identifies the function that created the error, and a skip of 1
+// identifies that function's caller. Negative skips are treated as 0.
+func WithSkip(skip int) Option {
+	return func(o *options) {
+		if skip < 0 {
+			skip = 0
+		}
+		o.skip = skip
+	}
+}
@@ -0,0 +1,103 @@
+package wrappederror
+
+import (
+	"testing"
+)
+
+func TestNewOptions(t *testing.T) {
+	c := newConfiguration()
+	o := newOptions(c, nil)
+
+	if o.captureCaller != c.CaptureCaller() ||
+		o.captureProcess != c.CaptureProcess() ||
+		o.captureMemory != c.CaptureMemory() ||
+		o.captureSourceFragments != c.CaptureSourceFragments() ||
+		o.sourceFragmentRadius != c.SourceFragmentRadius() ||
+		o.severity != nil ||
+		o.code != "" ||
+		o.kind != "" ||
+		o.skip != 0 ||
+		!o.report {
+		t.Errorf("Unexpected options %+v.\n", o)
+	}
+}
+
+func TestNewOptionsApplied(t *testing.T) {
+	c := newConfiguration()
+	o := newOptions(c, []Option{
+		WithoutCaller(),
+		WithoutProcess(),
+		WithoutMemory(),
+		WithoutReporting(),
+		WithFragmentRadius(5),
+		WithSeverity(testErrorSeverities.es0),
+		WithCode("E1"),
+		WithKind("network"),
+		WithSkip(2),
+	})
+
+	if o.captureCaller || o.captureProcess || o.captureMemory || o.report {
+		t.Errorf("Unexpected capture options %+v.\n", o)
+	}
+	if !o.captureSourceFragments || o.sourceFragmentRadius != 5 {
+		t.Errorf("Unexpected source fragment options %+v.\n", o)
+	}
+	if o.severity != testErrorSeverities.es0 {
+		t.Errorf("Unexpected severity %s.\n", o.severity)
+	}
+	if o.code != "E1" || o.kind != "network" {
+		t.Errorf("Unexpected code %s and kind %s.\n", o.code, o.kind)
+	}
+	if o.skip != 2 {
+		t.Errorf("Unexpected skip %d.\n", o.skip)
+	}
+
+	o = newOptions(c, []Option{WithoutSourceFragment()})
+	if o.captureSourceFragments {
+		t.Error("Expected no source fragments.")
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	e := NewWithOptions(nil, "test", WithoutCaller(), WithoutProcess())
+	if e.Caller != nil {
+		t.Error("Expected no caller.")
+	}
+	if e.Process != nil {
+		t.Error("Expected no process.")
+	}
+	if !packageState.config.CaptureCaller() ||
+		!packageState.config.CaptureProcess() {
+		t.Error("Expected an unmodified configuration.")
+	}
+
+	e = NewWithOptions(nil, "test")
+	if e.Caller.Function != "github.com/colinc86/wrappederror.TestNewWithOptions" {
+		t.Errorf("Unexpected caller %s.\n", e.Caller)
+	}
+}
+
+func TestNewWithOptionsSeverity(t *testing.T) {
+	e := NewWithOptions(nil, "test", WithSeverity(testErrorSeverities.es2))
+	if e.Metadata.Severity != testErrorSeverities.es2 {
+		t.Errorf("Unexpected severity %s.\n", e.Metadata.Severity)
+	}
+}
+
+func TestNewWithOptionsFragmentRadius(t *testing.T) {
+	e := NewWithOptions(nil, "test", WithFragmentRadius(0))
+	if f := e.Caller.Fragment(); f == nil || f.LowerLine != f.UpperLine {
+		t.Errorf("Unexpected source fragment %s.\n", f)
+	}
+}
+
+func TestNewWithOptionsSkip(t *testing.T) {
+	e := testNewWithOptionsSkip()
+	if e.Caller.Function != "github.com/colinc86/wrappederror.TestNewWithOptionsSkip" {
+		t.Errorf("Unexpected caller %s.\n", e.Caller)
+	}
+}
+
+func testNewWithOptionsSkip() *Error {
+	return NewWithOptions(nil, "test", WithSkip(1))
+}
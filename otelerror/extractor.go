@@ -0,0 +1,45 @@
+package otelerror
+
+import (
+	"context"
+
+	we "github.com/colinc86/wrappederror"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The keys of the fields extracted by the package's context extractors.
+const (
+	FieldTraceID = "traceID"
+	FieldSpanID  = "spanID"
+)
+
+// Exported functions
+
+// RegisterContextExtractors adds TraceIDExtractor and SpanIDExtractor to the
+// package wrappederror's configuration with the keys FieldTraceID and
+// FieldSpanID, so that errors created with a context.Context contain the IDs of
+// the context's span.
+func RegisterContextExtractors() {
+	we.Config().AddContextExtractor(FieldTraceID, TraceIDExtractor)
+	we.Config().AddContextExtractor(FieldSpanID, SpanIDExtractor)
+}
+
+// TraceIDExtractor extracts the trace ID of the span in the context as a
+// hexadecimal string.
+func TraceIDExtractor(ctx context.Context) (interface{}, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return nil, false
+	}
+	return sc.TraceID().String(), true
+}
+
+// SpanIDExtractor extracts the span ID of the span in the context as a
+// hexadecimal string.
+func SpanIDExtractor(ctx context.Context) (interface{}, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasSpanID() {
+		return nil, false
+	}
+	return sc.SpanID().String(), true
+}
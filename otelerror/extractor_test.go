@@ -0,0 +1,38 @@
+package otelerror
+
+import (
+	"context"
+	"testing"
+
+	we "github.com/colinc86/wrappederror"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestExtractors(t *testing.T) {
+	if _, ok := TraceIDExtractor(context.Background()); ok {
+		t.Error("Unexpected trace ID.")
+	}
+	if _, ok := SpanIDExtractor(context.Background()); ok {
+		t.Error("Unexpected span ID.")
+	}
+
+	RegisterContextExtractors()
+	defer we.Config().RemoveContextExtractor(FieldTraceID)
+	defer we.Config().RemoveContextExtractor(FieldSpanID)
+
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+
+	e := we.NewCtx(ctx, nil, "extractors")
+	sc := span.SpanContext()
+
+	if e.Metadata.Fields[FieldTraceID] != sc.TraceID().String() {
+		t.Errorf("Expected %s but received %v.\n", sc.TraceID(), e.Metadata.Fields[FieldTraceID])
+	}
+	if e.Metadata.Fields[FieldSpanID] != sc.SpanID().String() {
+		t.Errorf("Expected %s but received %v.\n", sc.SpanID(), e.Metadata.Fields[FieldSpanID])
+	}
+}
@@ -0,0 +1,132 @@
+// Package otelerror records errors created by package wrappederror on
+// OpenTelemetry spans.
+package otelerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	we "github.com/colinc86/wrappederror"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The name of the events that errors are recorded as.
+const eventName = "exception"
+
+// The keys of the attributes of recorded errors.
+const (
+	KeyExceptionType       = attribute.Key("exception.type")
+	KeyExceptionMessage    = attribute.Key("exception.message")
+	KeyExceptionStacktrace = attribute.Key("exception.stacktrace")
+	KeySeverityLevel       = attribute.Key("wrappederror.severity.level")
+	KeySeverityTitle       = attribute.Key("wrappederror.severity.title")
+	KeyIndex               = attribute.Key("wrappederror.index")
+	KeySimilar             = attribute.Key("wrappederror.similar")
+)
+
+// Recorder types record errors on spans as exception events.
+//
+// A recorder sets the status of a span to codes.Error when it records an error
+// with a severity level at least the recorder's threshold. Errors without a
+// severity have the level wrappederror.ErrorSeverityLevelNone.
+type Recorder struct {
+	threshold we.ErrorSeverityLevel
+}
+
+// Initializers
+
+// NewRecorder creates and returns a new recorder that sets the status of spans
+// to codes.Error for errors with a severity level at least threshold.
+//
+// Use wrappederror.ErrorSeverityLevelNone to set the status for every error.
+func NewRecorder(threshold we.ErrorSeverityLevel) *Recorder {
+	return &Recorder{threshold: threshold}
+}
+
+// Exported methods
+
+// Record records the error on the span in the context. If err is nil, or the
+// context's span isn't recording, then this method does nothing.
+func (r *Recorder) Record(ctx context.Context, err error) {
+	r.RecordSpan(trace.SpanFromContext(ctx), err)
+}
+
+// RecordSpan records the error on the span. If err is nil, or the span isn't
+// recording, then this method does nothing.
+//
+// The error is recorded as an exception event. If the error, or an error in
+// its tree, was created by package wrappederror, then the event has the
+// error's stack trace, severity, index and number of similar errors.
+func (r *Recorder) RecordSpan(span trace.Span, err error) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+
+	attrs := Attributes(err)
+	span.AddEvent(eventName, trace.WithAttributes(attrs...))
+
+	if errorLevel(err).AtLeast(r.threshold) {
+		span.SetStatus(codes.Error, message(err))
+	}
+}
+
+// Exported functions
+
+// Attributes returns the attributes of the exception event that the error is
+// recorded as.
+func Attributes(err error) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		KeyExceptionType.String(fmt.Sprintf("%T", err)),
+		KeyExceptionMessage.String(message(err)),
+	}
+
+	var e *we.Error
+	if !errors.As(err, &e) {
+		return attrs
+	}
+
+	if e.Caller != nil {
+		if st := e.Caller.StackTrace(); st != "" {
+			attrs = append(attrs, KeyExceptionStacktrace.String(st))
+		}
+	}
+
+	if m := e.Metadata; m != nil {
+		attrs = append(
+			attrs,
+			KeyIndex.Int(m.Index),
+			KeySimilar.Int(m.Similar),
+		)
+
+		if s := m.Severity; s != nil {
+			attrs = append(
+				attrs,
+				KeySeverityLevel.String(string(s.Level)),
+				KeySeverityTitle.String(s.Title),
+			)
+		}
+	}
+
+	return attrs
+}
+
+// Non-exported functions
+
+// message returns the message of the error with the package's redaction
+// patterns redacted.
+func message(err error) string {
+	return we.Redact(err.Error())
+}
+
+// errorLevel returns the severity level of the first error in the error's tree
+// that was created by package wrappederror.
+func errorLevel(err error) we.ErrorSeverityLevel {
+	var e *we.Error
+	if errors.As(err, &e) && e.Metadata != nil && e.Metadata.Severity != nil {
+		return e.Metadata.Severity.Level
+	}
+	return we.ErrorSeverityLevelNone
+}
@@ -0,0 +1,139 @@
+package otelerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	we "github.com/colinc86/wrappederror"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// testRecord records the error on a new span with the recorder and returns the
+// exported span.
+func testRecord(t *testing.T, r *Recorder, err error) tracetest.SpanStub {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(context.Background())
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+	r.Record(ctx, err)
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span but received %d.\n", len(spans))
+	}
+	return spans[0]
+}
+
+func testAttributes(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestRecorderRecord(t *testing.T) {
+	severity, _ := we.NewErrorSeverity("Timeout", "timeout", we.ErrorSeverityLevelHigh)
+	e := we.NewWithOptions(errors.New("i/o timeout"), "get failed", we.WithSeverity(severity))
+
+	s := testRecord(t, NewRecorder(we.ErrorSeverityLevelModerate), e)
+
+	if len(s.Events) != 1 || s.Events[0].Name != "exception" {
+		t.Fatalf("Expected an exception event but received %+v.\n", s.Events)
+	}
+
+	attrs := testAttributes(s.Events[0].Attributes)
+	for k, v := range map[attribute.Key]attribute.Value{
+		KeyExceptionType:    attribute.StringValue("*wrappederror.Error"),
+		KeyExceptionMessage: attribute.StringValue("get failed: i/o timeout"),
+		KeySeverityLevel:    attribute.StringValue("high"),
+		KeySeverityTitle:    attribute.StringValue("Timeout"),
+		KeyIndex:            attribute.IntValue(e.Metadata.Index),
+		KeySimilar:          attribute.IntValue(e.Metadata.Similar),
+	} {
+		if attrs[k] != v {
+			t.Errorf("Expected %s to be %s but received %s.\n", k, v.Emit(), attrs[k].Emit())
+		}
+	}
+
+	if st := attrs[KeyExceptionStacktrace].AsString(); !strings.Contains(st, "TestRecorderRecord") {
+		t.Errorf("Unexpected stack trace: %s\n", st)
+	}
+
+	if s.Status.Code != codes.Error || s.Status.Description != "get failed: i/o timeout" {
+		t.Errorf("Unexpected status: %+v\n", s.Status)
+	}
+}
+
+func TestRecorderThreshold(t *testing.T) {
+	low, _ := we.NewErrorSeverity("Low", "low", we.ErrorSeverityLevelLow)
+	severe, _ := we.NewErrorSeverity("Severe", "severe", we.ErrorSeverityLevelSevere)
+
+	t.Run("Recorder threshold 0", func(t *testing.T) {
+		testRecorderThreshold(t, we.ErrorSeverityLevelHigh, we.NewWithOptions(nil, "low", we.WithSeverity(low)), codes.Unset)
+	})
+	t.Run("Recorder threshold 1", func(t *testing.T) {
+		testRecorderThreshold(t, we.ErrorSeverityLevelHigh, we.NewWithOptions(nil, "severe", we.WithSeverity(severe)), codes.Error)
+	})
+	t.Run("Recorder threshold 2", func(t *testing.T) {
+		testRecorderThreshold(t, we.ErrorSeverityLevelLow, we.New(nil, "none"), codes.Unset)
+	})
+	t.Run("Recorder threshold 3", func(t *testing.T) {
+		testRecorderThreshold(t, we.ErrorSeverityLevelNone, we.New(nil, "none"), codes.Error)
+	})
+	t.Run("Recorder threshold 4", func(t *testing.T) {
+		err := fmt.Errorf("handler: %w", we.NewWithOptions(nil, "severe", we.WithSeverity(severe)))
+		testRecorderThreshold(t, we.ErrorSeverityLevelHigh, err, codes.Error)
+	})
+}
+
+func testRecorderThreshold(t *testing.T, threshold we.ErrorSeverityLevel, err error, e codes.Code) {
+	s := testRecord(t, NewRecorder(threshold), err)
+	if s.Status.Code != e {
+		t.Errorf("Expected %s but received %s.\n", e, s.Status.Code)
+	}
+}
+
+func TestRecorderForeignError(t *testing.T) {
+	defer we.Config().SetRedactionPatterns()
+	we.Config().SetRedactionPatterns(regexp.MustCompile(`token=(\S+)`))
+
+	s := testRecord(t, NewRecorder(we.ErrorSeverityLevelNone), errors.New("token=abc rejected"))
+	if len(s.Events) != 1 {
+		t.Fatalf("Expected 1 event but received %d.\n", len(s.Events))
+	}
+
+	attrs := testAttributes(s.Events[0].Attributes)
+	if m := attrs[KeyExceptionMessage].AsString(); m != "token=[REDACTED] rejected" {
+		t.Errorf("Unexpected message: %s\n", m)
+	}
+
+	for _, k := range []attribute.Key{KeyExceptionStacktrace, KeyIndex, KeySeverityLevel} {
+		if _, ok := attrs[k]; ok {
+			t.Errorf("Unexpected attribute %s.\n", k)
+		}
+	}
+}
+
+func TestRecorderNoop(t *testing.T) {
+	r := NewRecorder(we.ErrorSeverityLevelNone)
+
+	s := testRecord(t, r, nil)
+	if len(s.Events) != 0 || s.Status.Code != codes.Unset {
+		t.Errorf("Unexpected span: %+v\n", s)
+	}
+
+	// Recording on a context without a span does nothing.
+	r.Record(context.Background(), errors.New("no span"))
+	r.RecordSpan(trace.SpanFromContext(context.Background()), errors.New("no span"))
+}
@@ -0,0 +1,102 @@
+package wrappederror
+
+import "fmt"
+
+// The context of errors created from recovered panics.
+const panicErrorContext = "panic"
+
+// PanicError types contain the value of a recovered panic.
+type PanicError struct {
+
+	// The value passed to panic.
+	Value interface{}
+}
+
+// Initializers
+
+// FromPanic creates and returns a new error from the recovered panic value, v.
+// If v is nil, then nil is returned.
+//
+// The error wraps a *PanicError that contains v. When FromPanic is called by a
+// deferred function while the goroutine is panicking, the error's caller is
+// the function that panicked instead of the function that recovered, and its
+// stack frames are those of the panicking goroutine.
+//
+//	defer func() {
+//		if err := we.FromPanic(recover()); err != nil {
+//			log.Println(err.Trace())
+//		}
+//	}()
+func FromPanic(v interface{}) *Error {
+	if v == nil {
+		return nil
+	}
+
+	o := newOptions(packageState.config, nil)
+	o.recovered = true
+	return newError(&PanicError{Value: v}, panicErrorContext, o)
+}
+
+// Exported methods
+
+// Unwrap returns the panic value if it is an error, or nil otherwise.
+func (e PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Error interface methods
+
+func (e PanicError) Error() string {
+	if err, ok := e.Value.(error); ok {
+		return err.Error()
+	}
+	return fmt.Sprint(e.Value)
+}
+
+// Exported functions
+
+// Recover recovers from a panic and sets the error pointed to by errp to an
+// error created from the recovered value. It must be deferred directly.
+//
+//	func f() (err error) {
+//		defer we.Recover(&err)
+//		...
+//	}
+//
+// If the goroutine isn't panicking, then Recover does nothing.
+func Recover(errp *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	o := newOptions(packageState.config, nil)
+	o.recovered = true
+	e := newError(&PanicError{Value: v}, panicErrorContext, o)
+
+	if errp != nil {
+		*errp = e
+	}
+}
+
+// Go calls f in a new goroutine and returns a channel that receives the error
+// that f returns, or an error created from its panic if f panics. The channel
+// is closed after the error is sent.
+func Go(f func() error) <-chan error {
+	c := make(chan error, 1)
+
+	go func() {
+		defer close(c)
+
+		var err error
+		defer func() { c <- err }()
+		defer Recover(&err)
+
+		err = f()
+	}()
+
+	return c
+}
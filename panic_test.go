@@ -0,0 +1,155 @@
+package wrappederror
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func testPanicString() {
+	panic("boom")
+}
+
+func testPanicIndex() {
+	var s []int
+	_ = s[len(s)+1]
+}
+
+func testPanicError() {
+	panic(errors.New("failed"))
+}
+
+func testRepanic() {
+	defer func() {
+		v := recover()
+		panic(v.(string) + " again")
+	}()
+	panic("boom")
+}
+
+func testRecoverPanic(f func()) (err error) {
+	defer Recover(&err)
+	f()
+	return nil
+}
+
+func testFromPanic(f func()) (e *Error) {
+	defer func() {
+		e = FromPanic(recover())
+	}()
+	f()
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("Recover 0", func(t *testing.T) {
+		testRecover(t, testPanicString, "panic: boom", "testPanicString")
+	})
+	t.Run("Recover 1", func(t *testing.T) {
+		testRecover(t, testPanicIndex, "panic: runtime error: index out of range [1] with length 0", "testPanicIndex")
+	})
+	t.Run("Recover 2", func(t *testing.T) {
+		testRecover(t, testPanicError, "panic: failed", "testPanicError")
+	})
+	t.Run("Recover 3", func(t *testing.T) {
+		testRecover(t, testRepanic, "panic: boom again", "testRepanic.func1")
+	})
+}
+
+func testRecover(t *testing.T, f func(), message string, function string) {
+	err := testRecoverPanic(f)
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Expected *Error but received %T.\n", err)
+	}
+
+	testPanicErrorValue(t, e, message, function)
+}
+
+func TestFromPanic(t *testing.T) {
+	t.Run("From panic 0", func(t *testing.T) {
+		testPanicErrorValue(t, testFromPanic(testPanicString), "panic: boom", "testPanicString")
+	})
+	t.Run("From panic 1", func(t *testing.T) {
+		testPanicErrorValue(t, testFromPanic(testPanicIndex), "panic: runtime error: index out of range [1] with length 0", "testPanicIndex")
+	})
+	t.Run("From panic 2", func(t *testing.T) {
+		testPanicErrorValue(t, testFromPanic(testRepanic), "panic: boom again", "testRepanic.func1")
+	})
+
+	if FromPanic(nil) != nil {
+		t.Error("Expected nil.")
+	}
+}
+
+func testPanicErrorValue(t *testing.T, e *Error, message string, function string) {
+	if e.Error() != message {
+		t.Errorf("Expected \"%s\" but received \"%s\".\n", message, e.Error())
+	}
+
+	if !strings.HasSuffix(e.Caller.Function, "."+function) {
+		t.Errorf("Expected caller %s but received %s.\n", function, e.Caller.Function)
+	}
+
+	frames := e.Caller.Frames()
+	if len(frames) == 0 || frames[0].Function != e.Caller.Function {
+		t.Errorf("Expected frames to begin with %s.\n", e.Caller.Function)
+	}
+
+	var pe *PanicError
+	if !errors.As(e, &pe) {
+		t.Error("Expected panic error.")
+	}
+}
+
+func TestRecoverRuntimeError(t *testing.T) {
+	err := testRecoverPanic(testPanicIndex)
+
+	var re runtime.Error
+	if !errors.As(err, &re) {
+		t.Errorf("Expected runtime error but received %v.\n", err)
+	}
+}
+
+func TestRecoverNoPanic(t *testing.T) {
+	if err := testRecoverPanic(func() {}); err != nil {
+		t.Errorf("Expected nil but received %s.\n", err)
+	}
+}
+
+func TestGo(t *testing.T) {
+	if err := <-Go(func() error { return nil }); err != nil {
+		t.Errorf("Expected nil but received %s.\n", err)
+	}
+
+	e := errors.New("failed")
+	if err := <-Go(func() error { return e }); err != e {
+		t.Errorf("Expected %s but received %v.\n", e, err)
+	}
+
+	c := Go(func() error {
+		testPanicString()
+		return nil
+	})
+
+	err := <-c
+	var we *Error
+	if !errors.As(err, &we) || !strings.HasSuffix(we.Caller.Function, ".testPanicString") {
+		t.Errorf("Unexpected error %v.\n", err)
+	}
+
+	if _, ok := <-c; ok {
+		t.Error("Expected closed channel.")
+	}
+}
+
+func TestPanicErrorUnwrap(t *testing.T) {
+	e := errors.New("failed")
+	if (PanicError{Value: e}).Unwrap() != e {
+		t.Error("Expected error.")
+	}
+	if (PanicError{Value: "failed"}).Unwrap() != nil {
+		t.Error("Expected nil.")
+	}
+}
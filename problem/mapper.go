@@ -0,0 +1,164 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	we "github.com/colinc86/wrappederror"
+)
+
+// The prefix of a problem's instance. The error's index is appended to it.
+const instancePrefix = "urn:wrappederror:error:"
+
+// Mapper types map errors to HTTP status codes and problems.
+//
+// A mapper looks for a status code in the following order, and uses the first
+// one it finds.
+//
+//  1. The status registered with the code of an error in the error tree.
+//  2. The status registered with the level of the error's severity.
+//  3. The mapper's default status.
+type Mapper struct {
+	codes         map[we.ErrorCode]int
+	levels        map[we.ErrorSeverityLevel]int
+	defaultStatus int
+	fullDetail    bool
+	mutex         *sync.RWMutex
+}
+
+// Option types set the options of a mapper.
+type Option func(m *Mapper)
+
+// Initializers
+
+// NewMapper creates and returns a new mapper that maps errors without a
+// registered code or severity level to defaultStatus.
+func NewMapper(defaultStatus int, opts ...Option) *Mapper {
+	m := &Mapper{
+		codes:         make(map[we.ErrorCode]int),
+		levels:        make(map[we.ErrorSeverityLevel]int),
+		defaultStatus: defaultStatus,
+		mutex:         new(sync.RWMutex),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Options
+
+// WithFullDetail sets each problem's detail to the output of the error's Error
+// method, which includes the text of every error in the error's chain.
+//
+// Inner errors often describe internal failures, such as database errors or
+// recovered panics, so only use this option when the chain is safe to send to
+// clients.
+func WithFullDetail() Option {
+	return func(m *Mapper) {
+		m.fullDetail = true
+	}
+}
+
+// Exported methods
+
+// RegisterCode maps errors with the given code to the HTTP status code,
+// status.
+func (m *Mapper) RegisterCode(code we.ErrorCode, status int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.codes[code] = status
+}
+
+// RegisterLevel maps errors with the given severity level to the HTTP status
+// code, status.
+func (m *Mapper) RegisterLevel(level we.ErrorSeverityLevel, status int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.levels[level] = status
+}
+
+// Status returns the HTTP status code of the error.
+func (m *Mapper) Status(err error) int {
+	var e *we.Error
+	if !errors.As(err, &e) {
+		return m.defaultStatus
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, code := range we.Codes(e) {
+		if s, ok := m.codes[code]; ok {
+			return s
+		}
+	}
+
+	if e.Metadata != nil && e.Metadata.Severity != nil {
+		if s, ok := m.levels[e.Metadata.Severity.Level]; ok {
+			return s
+		}
+	}
+
+	return m.defaultStatus
+}
+
+// Problem returns the error as a problem.
+//
+// The problem's title is the title of the error's severity, or the text of its
+// status code if it doesn't have one. The problem's detail is the context of
+// the outermost error created by package wrappederror, or empty if there isn't
+// one, and its instance identifies the error's index. Use WithFullDetail to
+// include the rest of the error's chain in the detail.
+//
+// Errors created by package wrappederror add their code, kind and severity
+// level as the problem's "code", "kind" and "severity" extensions.
+func (m *Mapper) Problem(err error) *Problem {
+	status := m.Status(err)
+	p := &Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+
+	if m.fullDetail {
+		p.Detail = err.Error()
+	}
+
+	var e *we.Error
+	if !errors.As(err, &e) {
+		return p
+	}
+
+	if !m.fullDetail {
+		p.Detail = e.FormatString(string(we.ErrorFormatTokenContext))
+	}
+
+	if e.Metadata == nil {
+		return p
+	}
+
+	p.Instance = fmt.Sprintf("%s%d", instancePrefix, e.Metadata.Index)
+	p.Extensions = make(map[string]interface{})
+
+	if e.Metadata.Severity != nil {
+		p.Title = e.Metadata.Severity.Title
+		p.Extensions["severity"] = e.Metadata.Severity.Level
+	}
+
+	if e.Code() != "" {
+		p.Extensions["code"] = e.Code()
+	}
+
+	if e.Kind() != "" {
+		p.Extensions["kind"] = e.Kind()
+	}
+
+	return p
+}
+
+// Write writes the error to w as a problem.
+func (m *Mapper) Write(w http.ResponseWriter, err error) error {
+	return m.Problem(err).Write(w)
+}
@@ -0,0 +1,138 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	we "github.com/colinc86/wrappederror"
+)
+
+func testMapper() *Mapper {
+	m := NewMapper(http.StatusInternalServerError)
+	m.RegisterCode("NOT_FOUND", http.StatusNotFound)
+	m.RegisterCode("CONFLICT", http.StatusConflict)
+	m.RegisterLevel(we.ErrorSeverityLevelLow, http.StatusBadRequest)
+	return m
+}
+
+func TestMapperStatus(t *testing.T) {
+	m := testMapper()
+	low, _ := we.NewErrorSeverity("Low", "low", we.ErrorSeverityLevelLow)
+	high, _ := we.NewErrorSeverity("High", "high", we.ErrorSeverityLevelHigh)
+
+	notFound := we.NewWithOptions(nil, "missing", we.WithCode("NOT_FOUND"))
+
+	t.Run("Mapper status 0", func(t *testing.T) {
+		testMapperStatus(t, m, errors.New("foreign"), http.StatusInternalServerError)
+	})
+	t.Run("Mapper status 1", func(t *testing.T) {
+		testMapperStatus(t, m, notFound, http.StatusNotFound)
+	})
+	t.Run("Mapper status 2", func(t *testing.T) {
+		testMapperStatus(t, m, we.New(notFound, "lookup"), http.StatusNotFound)
+	})
+	t.Run("Mapper status 3", func(t *testing.T) {
+		testMapperStatus(t, m, fmt.Errorf("handler: %w", notFound), http.StatusNotFound)
+	})
+	t.Run("Mapper status 4", func(t *testing.T) {
+		testMapperStatus(t, m, we.NewWithOptions(nil, "bad", we.WithSeverity(low)), http.StatusBadRequest)
+	})
+	t.Run("Mapper status 5", func(t *testing.T) {
+		testMapperStatus(t, m, we.NewWithOptions(nil, "bad", we.WithSeverity(high)), http.StatusInternalServerError)
+	})
+	t.Run("Mapper status 6", func(t *testing.T) {
+		testMapperStatus(t, m, we.NewWithOptions(nil, "x", we.WithCode("UNKNOWN")), http.StatusInternalServerError)
+	})
+	t.Run("Mapper status 7", func(t *testing.T) {
+		e := we.NewWithOptions(notFound, "conflict", we.WithCode("CONFLICT"), we.WithSeverity(low))
+		testMapperStatus(t, m, e, http.StatusConflict)
+	})
+}
+
+func testMapperStatus(t *testing.T, m *Mapper, err error, ex int) {
+	if s := m.Status(err); s != ex {
+		t.Errorf("Expected %d but received %d.\n", ex, s)
+	}
+}
+
+func TestMapperProblem(t *testing.T) {
+	m := testMapper()
+	s, _ := we.NewErrorSeverity("Lookup Failed", "x", we.ErrorSeverityLevelModerate)
+	e := we.NewWithOptions(
+		errors.New("no rows"),
+		"user lookup failed",
+		we.WithCode("NOT_FOUND"),
+		we.WithKind("database"),
+		we.WithSeverity(s),
+	)
+
+	p := m.Problem(e)
+	if p.Status != http.StatusNotFound {
+		t.Errorf("Expected %d but received %d.\n", http.StatusNotFound, p.Status)
+	}
+	if p.Title != "Lookup Failed" {
+		t.Errorf("Expected %s but received %s.\n", "Lookup Failed", p.Title)
+	}
+	if p.Detail != "user lookup failed" {
+		t.Errorf("Expected %s but received %s.\n", "user lookup failed", p.Detail)
+	}
+
+	instance := fmt.Sprintf("%s%d", instancePrefix, e.Metadata.Index)
+	if p.Instance != instance {
+		t.Errorf("Expected %s but received %s.\n", instance, p.Instance)
+	}
+
+	if p.Extensions["code"] != we.ErrorCode("NOT_FOUND") ||
+		p.Extensions["kind"] != we.ErrorKind("database") ||
+		p.Extensions["severity"] != we.ErrorSeverityLevelModerate {
+		t.Errorf("Unexpected extensions %v.\n", p.Extensions)
+	}
+
+	p = m.Problem(errors.New("foreign"))
+	if p.Title != http.StatusText(http.StatusInternalServerError) ||
+		p.Detail != "" ||
+		p.Instance != "" ||
+		p.Extensions != nil {
+		t.Errorf("Unexpected problem %+v.\n", p)
+	}
+}
+
+func TestMapperProblemFullDetail(t *testing.T) {
+	m := NewMapper(http.StatusInternalServerError, WithFullDetail())
+	e := we.New(errors.New("no rows"), "user lookup failed")
+
+	if p := m.Problem(e); p.Detail != e.Error() {
+		t.Errorf("Expected %s but received %s.\n", e.Error(), p.Detail)
+	}
+
+	if p := m.Problem(errors.New("foreign")); p.Detail != "foreign" {
+		t.Errorf("Expected %s but received %s.\n", "foreign", p.Detail)
+	}
+}
+
+func TestMapperWrite(t *testing.T) {
+	m := testMapper()
+	rec := httptest.NewRecorder()
+	e := we.NewWithOptions(nil, "missing", we.WithCode("NOT_FOUND"))
+
+	if err := m.Write(rec, e); err != nil {
+		t.Fatalf("Error writing problem: %s\n", err)
+	}
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected %d but received %d.\n", http.StatusNotFound, rec.Code)
+	}
+
+	var p Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatalf("Error unmarshaling problem: %s\n", err)
+	}
+
+	if p.Detail != "missing" || p.Extensions["code"] != "NOT_FOUND" {
+		t.Errorf("Unexpected problem %+v.\n", p)
+	}
+}
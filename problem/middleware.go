@@ -0,0 +1,84 @@
+package problem
+
+import (
+	"fmt"
+	"net/http"
+
+	we "github.com/colinc86/wrappederror"
+)
+
+// Exported methods
+
+// Middleware returns a handler that calls next and recovers from its panics.
+//
+// A recovered panic is converted to an error with FromPanic, wrapped in an
+// error with the request's method and path as its context, and is written to
+// the response as a problem. If the handler has already written the response's
+// headers, then the error is still created, but the problem isn't written.
+// Panics with the value http.ErrAbortHandler are not recovered.
+func (m *Mapper) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		w := &responseWriter{ResponseWriter: rw}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			err := we.New(we.FromPanic(v), requestContext(r))
+			if !w.wroteHeader {
+				m.Write(rw, err)
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// responseWriter types keep track of whether or not a handler has written a
+// response's headers.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader writes the response's headers with the status code, code.
+// Informational status codes don't write the final headers.
+func (w *responseWriter) WriteHeader(code int) {
+	if code >= http.StatusOK {
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes data to the response, writing its headers first if they haven't
+// been written.
+func (w *responseWriter) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush sends buffered data to the client if the underlying writer supports
+// it.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for use with http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Non-exported functions
+
+// requestContext returns the context of errors recovered while handling r.
+func requestContext(r *http.Request) string {
+	return fmt.Sprintf("%s %s", r.Method, r.URL.Path)
+}
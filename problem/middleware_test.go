@@ -0,0 +1,134 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	m := testMapper()
+
+	t.Run("Middleware 0", func(t *testing.T) {
+		testMiddleware(t, m, func() { panic("boom") }, "GET /users")
+	})
+	t.Run("Middleware 1", func(t *testing.T) {
+		testMiddleware(t, m, func() { panic(errors.New("failed")) }, "GET /users")
+	})
+
+	m = NewMapper(http.StatusInternalServerError, WithFullDetail())
+
+	t.Run("Middleware 2", func(t *testing.T) {
+		testMiddleware(t, m, func() { panic("boom") }, "GET /users: panic: boom")
+	})
+	t.Run("Middleware 3", func(t *testing.T) {
+		testMiddleware(t, m, func() { panic(errors.New("failed")) }, "GET /users: panic: failed")
+	})
+	t.Run("Middleware 4", func(t *testing.T) {
+		testMiddleware(t, m, func() {
+			var s []int
+			_ = s[1]
+		}, "GET /users: panic: runtime error: index out of range [1] with length 0")
+	})
+}
+
+func testMiddleware(t *testing.T, m *Mapper, f func(), detail string) {
+	h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f()
+	}))
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/users")
+	if err != nil {
+		t.Fatalf("Error making request: %s\n", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected %d but received %d.\n", http.StatusInternalServerError, res.StatusCode)
+	}
+
+	if ct := res.Header.Get("Content-Type"); ct != ContentType {
+		t.Errorf("Expected %s but received %s.\n", ContentType, ct)
+	}
+
+	var p Problem
+	if err := json.NewDecoder(res.Body).Decode(&p); err != nil {
+		t.Fatalf("Error decoding problem: %s\n", err)
+	}
+
+	if p.Detail != detail {
+		t.Errorf("Expected %s but received %s.\n", detail, p.Detail)
+	}
+
+	if !strings.HasPrefix(p.Instance, instancePrefix) {
+		t.Errorf("Unexpected instance %s.\n", p.Instance)
+	}
+}
+
+func TestMiddlewareNoPanic(t *testing.T) {
+	h := testMapper().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected %d but received %d.\n", http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestMiddlewareAbortHandler(t *testing.T) {
+	h := testMapper().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("Expected %v but received %v.\n", http.ErrAbortHandler, v)
+		}
+	}()
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestMiddlewareHeadersWritten(t *testing.T) {
+	h := testMapper().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("partial"))
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("Expected %d but received %d.\n", http.StatusAccepted, rec.Code)
+	}
+
+	if b := rec.Body.String(); b != "partial" {
+		t.Errorf("Expected partial but received %s.\n", b)
+	}
+}
+
+func TestMiddlewareFlush(t *testing.T) {
+	h := testMapper().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Unexpected error: %s\n", err)
+		}
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !rec.Flushed || rec.Body.Len() != 0 {
+		t.Errorf("Expected an empty flushed response but received %s.\n", rec.Body)
+	}
+}
@@ -0,0 +1,140 @@
+// Package problem renders errors created by package wrappederror as HTTP
+// problem details (RFC 7807) with the application/problem+json media type.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// The problem type used when a problem doesn't specify one.
+const typeBlank = "about:blank"
+
+// Problem types describe an HTTP problem as defined by RFC 7807.
+type Problem struct {
+
+	// A URI reference that identifies the problem type.
+	Type string `json:"type,omitempty"`
+
+	// A short, human-readable summary of the problem type.
+	Title string `json:"title,omitempty"`
+
+	// The HTTP status code of the response.
+	Status int `json:"status,omitempty"`
+
+	// A human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+
+	// A URI reference that identifies this occurrence of the problem.
+	Instance string `json:"instance,omitempty"`
+
+	// Additional members of the problem.
+	//
+	// Extensions are marshaled as top-level members of the problem's JSON
+	// object. Extensions with the same name as a standard member are ignored.
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// Exported methods
+
+// Write writes the problem to w with the problem's status code and the
+// application/problem+json content type.
+func (p Problem) Write(w http.ResponseWriter) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	status := p.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return err
+}
+
+// JSON Marshaler interface methods
+
+// MarshalJSON marshals the problem and its extensions in to a single JSON
+// object.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+
+	t := p.Type
+	if t == "" {
+		t = typeBlank
+	}
+
+	m["type"] = t
+	setMember(m, "title", p.Title)
+	setMember(m, "detail", p.Detail)
+	setMember(m, "instance", p.Instance)
+	if p.Status != 0 {
+		m["status"] = p.Status
+	} else {
+		delete(m, "status")
+	}
+
+	return json.Marshal(m)
+}
+
+// JSON Unmarshaler interface methods
+
+// UnmarshalJSON unmarshals a problem's JSON object. Members that aren't
+// standard members are stored in the problem's extensions.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	var s struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance"`
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*p = Problem{
+		Type:     s.Type,
+		Title:    s.Title,
+		Status:   s.Status,
+		Detail:   s.Detail,
+		Instance: s.Instance,
+	}
+
+	for _, k := range []string{"type", "title", "status", "detail", "instance"} {
+		delete(m, k)
+	}
+
+	if len(m) > 0 {
+		p.Extensions = m
+	}
+
+	return nil
+}
+
+// Non-exported functions
+
+// setMember sets the member, k, of m to v if v isn't empty, and removes it
+// otherwise.
+func setMember(m map[string]interface{}, k string, v string) {
+	if v == "" {
+		delete(m, k)
+		return
+	}
+	m[k] = v
+}
@@ -0,0 +1,81 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblemMarshalJSON(t *testing.T) {
+	p := Problem{
+		Title:  "Not Found",
+		Status: http.StatusNotFound,
+		Detail: "missing",
+		Extensions: map[string]interface{}{
+			"code":  "E1",
+			"title": "ignored",
+		},
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Error marshaling problem: %s\n", err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Error unmarshaling problem: %s\n", err)
+	}
+
+	ex := map[string]interface{}{
+		"type":   "about:blank",
+		"title":  "Not Found",
+		"status": float64(http.StatusNotFound),
+		"detail": "missing",
+		"code":   "E1",
+	}
+
+	if len(m) != len(ex) {
+		t.Errorf("Expected %v but received %v.\n", ex, m)
+	}
+
+	for k, v := range ex {
+		if m[k] != v {
+			t.Errorf("Expected %s %v but received %v.\n", k, v, m[k])
+		}
+	}
+}
+
+func TestProblemUnmarshalJSON(t *testing.T) {
+	data := []byte(`{"type":"about:blank","title":"t","status":400,"instance":"i","code":"E1"}`)
+
+	var p Problem
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("Error unmarshaling problem: %s\n", err)
+	}
+
+	if p.Title != "t" || p.Status != 400 || p.Instance != "i" {
+		t.Errorf("Unexpected problem %+v.\n", p)
+	}
+
+	if len(p.Extensions) != 1 || p.Extensions["code"] != "E1" {
+		t.Errorf("Unexpected extensions %v.\n", p.Extensions)
+	}
+}
+
+func TestProblemWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	p := Problem{Title: "Conflict", Status: http.StatusConflict}
+	if err := p.Write(rec); err != nil {
+		t.Fatalf("Error writing problem: %s\n", err)
+	}
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected %d but received %d.\n", http.StatusConflict, rec.Code)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Expected %s but received %s.\n", ContentType, ct)
+	}
+}
@@ -30,10 +30,14 @@ type Process struct {
 
 // Initializers
 
-// newProcess creates and returns a new process.
-func newProcess() *Process {
-	ms := new(runtime.MemStats)
-	runtime.ReadMemStats(ms)
+// newProcess creates and returns a new process. Memory statistics are only
+// read if captureMemory is true.
+func newProcess(captureMemory bool) *Process {
+	var ms *runtime.MemStats
+	if captureMemory {
+		ms = new(runtime.MemStats)
+		runtime.ReadMemStats(ms)
+	}
 
 	return &Process{
 		runtime.NumGoroutine(),
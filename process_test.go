@@ -19,11 +19,25 @@ func TestNewProcess(t *testing.T) {
 		t.Errorf("Unreasonable cgos: %d\n", we.Process.CGO)
 	}
 
-	if we.Process.Memory == nil {
+	if we.Process.Memory != nil {
+		t.Error("Expected no memory statistics.")
+	}
+}
+
+func TestNewProcessMemory(t *testing.T) {
+	p := newProcess(true)
+	if p.Memory == nil {
 		t.Error("Expected memory statistics.")
 	}
 }
 
+func TestNewProcessNoMemory(t *testing.T) {
+	p := newProcess(false)
+	if p.Memory != nil {
+		t.Error("Expected no memory statistics.")
+	}
+}
+
 func TestProcessIgnoreBreakpoints(t *testing.T) {
 	packageState.config.SetIgnoreBreakpoints(true)
 	we := New(nil, "test")
@@ -0,0 +1,217 @@
+// Package promerror exposes metrics about the errors created by package
+// wrappederror through a Prometheus collector.
+package promerror
+
+import (
+	"sync"
+
+	we "github.com/colinc86/wrappederror"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OverflowLabel is the label value of the errors counted after a label's
+// cardinality limit is reached.
+const OverflowLabel = "_other"
+
+// Default values of a collector's options.
+const (
+	defaultNamespace       = "wrappederror"
+	defaultMaxFunctions    = 100
+	defaultMaxFingerprints = 100
+)
+
+// The default buckets of the chain depth histogram.
+var defaultDepthBuckets = []float64{0, 1, 2, 3, 4, 6, 8, 12, 16}
+
+// Collector types collect metrics about errors.
+//
+// Collectors implement the prometheus.Collector interface, so they can be
+// registered with a Prometheus registry, and the wrappederror.Observer
+// interface, so they can be registered with the RegisterObserver function.
+// Observers are called synchronously as errors are created, so every error is
+// counted, even when errors are created in bursts.
+//
+// A collector exposes the following metrics, where namespace defaults to
+// "wrappederror".
+//
+//	namespace_errors_total{level}
+//	namespace_errors_by_function_total{function}
+//	namespace_errors_by_fingerprint_total{fingerprint}
+//	namespace_error_chain_depth
+//
+// Errors without a severity are counted with the level "none". Errors that
+// didn't capture their call information aren't counted by function, and errors
+// without a fingerprint aren't counted by fingerprint.
+type Collector struct {
+	levels       *prometheus.CounterVec
+	functions    *limitedCounterVec
+	fingerprints *limitedCounterVec
+	depth        prometheus.Histogram
+}
+
+// Option types set the options of a collector.
+type Option func(o *options)
+
+// options types contain a collector's options.
+type options struct {
+	namespace       string
+	maxFunctions    int
+	maxFingerprints int
+	depthBuckets    []float64
+}
+
+// limitedCounterVec types are counter vectors with a single label and a limit on
+// the number of label values.
+type limitedCounterVec struct {
+	counters *prometheus.CounterVec
+	max      int
+	values   map[string]struct{}
+	mutex    *sync.Mutex
+}
+
+// Initializers
+
+// NewCollector creates and returns a new collector.
+func NewCollector(opts ...Option) *Collector {
+	o := options{
+		namespace:       defaultNamespace,
+		maxFunctions:    defaultMaxFunctions,
+		maxFingerprints: defaultMaxFingerprints,
+		depthBuckets:    defaultDepthBuckets,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Collector{
+		levels: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "errors_total",
+			Help:      "The number of errors created by severity level.",
+		}, []string{"level"}),
+		functions: newLimitedCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "errors_by_function_total",
+			Help:      "The number of errors created by the function that created them.",
+		}, "function", o.maxFunctions),
+		fingerprints: newLimitedCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "errors_by_fingerprint_total",
+			Help:      "The number of errors created by fingerprint.",
+		}, "fingerprint", o.maxFingerprints),
+		depth: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: o.namespace,
+			Name:      "error_chain_depth",
+			Help:      "The depth of the chains of the errors created.",
+			Buckets:   o.depthBuckets,
+		}),
+	}
+}
+
+// newLimitedCounterVec creates and returns a new counter vector with the label
+// that counts at most max label values.
+func newLimitedCounterVec(
+	opts prometheus.CounterOpts,
+	label string,
+	max int,
+) *limitedCounterVec {
+	return &limitedCounterVec{
+		counters: prometheus.NewCounterVec(opts, []string{label}),
+		max:      max,
+		values:   make(map[string]struct{}),
+		mutex:    new(sync.Mutex),
+	}
+}
+
+// Options
+
+// WithNamespace sets the namespace of the collector's metrics.
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithMaxFunctions sets the maximum number of functions that errors are counted
+// by. Once the limit is reached, errors created by other functions are counted
+// with the function OverflowLabel.
+func WithMaxFunctions(n int) Option {
+	return func(o *options) {
+		o.maxFunctions = n
+	}
+}
+
+// WithMaxFingerprints sets the maximum number of fingerprints that errors are
+// counted by. Once the limit is reached, errors with other fingerprints are
+// counted with the fingerprint OverflowLabel.
+func WithMaxFingerprints(n int) Option {
+	return func(o *options) {
+		o.maxFingerprints = n
+	}
+}
+
+// WithDepthBuckets sets the buckets of the chain depth histogram.
+func WithDepthBuckets(buckets ...float64) Option {
+	return func(o *options) {
+		o.depthBuckets = append([]float64(nil), buckets...)
+	}
+}
+
+// Exported methods
+
+// Observe adds the error, e, to the collector's metrics.
+func (c *Collector) Observe(e we.Error) {
+	level := we.ErrorSeverityLevelNone
+	if m := e.Metadata; m != nil {
+		if m.Severity != nil {
+			level = m.Severity.Level
+		}
+
+		if m.Fingerprint != "" {
+			c.fingerprints.inc(m.Fingerprint)
+		}
+	}
+	c.levels.WithLabelValues(string(level)).Inc()
+
+	if e.Caller != nil {
+		c.functions.inc(e.Caller.Function)
+	}
+
+	c.depth.Observe(float64(e.Depth()))
+}
+
+// Prometheus Collector interface methods
+
+// Describe sends the descriptors of the collector's metrics to ch.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.levels.Describe(ch)
+	c.functions.counters.Describe(ch)
+	c.fingerprints.counters.Describe(ch)
+	c.depth.Describe(ch)
+}
+
+// Collect sends the collector's metrics to ch.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.levels.Collect(ch)
+	c.functions.counters.Collect(ch)
+	c.fingerprints.counters.Collect(ch)
+	c.depth.Collect(ch)
+}
+
+// Non-exported methods
+
+// inc increments the counter with the label value, or the overflow counter if
+// the value is new and the vector's limit has been reached.
+func (v *limitedCounterVec) inc(value string) {
+	v.mutex.Lock()
+	if _, ok := v.values[value]; !ok {
+		if len(v.values) < v.max {
+			v.values[value] = struct{}{}
+		} else {
+			value = OverflowLabel
+		}
+	}
+	v.mutex.Unlock()
+
+	v.counters.WithLabelValues(value).Inc()
+}
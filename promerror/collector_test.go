@@ -0,0 +1,120 @@
+package promerror
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	we "github.com/colinc86/wrappederror"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollectorLevels(t *testing.T) {
+	c := NewCollector()
+	high, _ := we.NewErrorSeverity("High", "high", we.ErrorSeverityLevelHigh)
+
+	c.Observe(*we.NewWithOptions(nil, "a", we.WithSeverity(high), we.WithoutCaller()))
+	c.Observe(*we.NewWithOptions(nil, "b", we.WithSeverity(high), we.WithoutCaller()))
+	c.Observe(*we.NewWithOptions(errors.New("c"), "c", we.WithoutCaller()))
+
+	e := `
+# HELP wrappederror_errors_total The number of errors created by severity level.
+# TYPE wrappederror_errors_total counter
+wrappederror_errors_total{level="high"} 2
+wrappederror_errors_total{level="none"} 1
+# HELP wrappederror_error_chain_depth The depth of the chains of the errors created.
+# TYPE wrappederror_error_chain_depth histogram
+wrappederror_error_chain_depth_bucket{le="0"} 2
+wrappederror_error_chain_depth_bucket{le="1"} 3
+wrappederror_error_chain_depth_bucket{le="2"} 3
+wrappederror_error_chain_depth_bucket{le="3"} 3
+wrappederror_error_chain_depth_bucket{le="4"} 3
+wrappederror_error_chain_depth_bucket{le="6"} 3
+wrappederror_error_chain_depth_bucket{le="8"} 3
+wrappederror_error_chain_depth_bucket{le="12"} 3
+wrappederror_error_chain_depth_bucket{le="16"} 3
+wrappederror_error_chain_depth_bucket{le="+Inf"} 3
+wrappederror_error_chain_depth_sum 1
+wrappederror_error_chain_depth_count 3
+`
+	err := testutil.CollectAndCompare(
+		c,
+		strings.NewReader(e),
+		"wrappederror_errors_total",
+		"wrappederror_error_chain_depth",
+	)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollectorFingerprints(t *testing.T) {
+	c := NewCollector(WithNamespace("app"), WithMaxFingerprints(2))
+
+	for _, fp := range []string{"a", "b", "a", "c", "d", "b"} {
+		c.Observe(*we.NewWithOptions(nil, "fingerprint", we.WithFingerprint(fp), we.WithoutCaller()))
+	}
+	c.Observe(*we.NewWithOptions(nil, "no fingerprint", we.WithoutCaller()))
+
+	e := `
+# HELP app_errors_by_fingerprint_total The number of errors created by fingerprint.
+# TYPE app_errors_by_fingerprint_total counter
+app_errors_by_fingerprint_total{fingerprint="a"} 2
+app_errors_by_fingerprint_total{fingerprint="b"} 2
+app_errors_by_fingerprint_total{fingerprint="_other"} 2
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(e), "app_errors_by_fingerprint_total")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func testCollectorFunctionA() we.Error {
+	return *we.New(nil, "a")
+}
+
+func testCollectorFunctionB() we.Error {
+	return *we.New(nil, "b")
+}
+
+func TestCollectorFunctions(t *testing.T) {
+	c := NewCollector(WithMaxFunctions(1))
+
+	c.Observe(testCollectorFunctionA())
+	c.Observe(testCollectorFunctionB())
+	c.Observe(testCollectorFunctionA())
+	c.Observe(*we.NewWithOptions(nil, "no caller", we.WithoutCaller()))
+
+	e := `
+# HELP wrappederror_errors_by_function_total The number of errors created by the function that created them.
+# TYPE wrappederror_errors_by_function_total counter
+wrappederror_errors_by_function_total{function="github.com/colinc86/wrappederror/promerror.testCollectorFunctionA"} 2
+wrappederror_errors_by_function_total{function="_other"} 1
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(e), "wrappederror_errors_by_function_total")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollectorObserver(t *testing.T) {
+	c := NewCollector(WithDepthBuckets(1, 2))
+	if err := we.RegisterObserver("promerror", c); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	defer we.UnregisterObserver("promerror")
+
+	// More errors than fit in a reporter's queue are all counted.
+	n := 2 * we.Config().ReporterQueueSize()
+	for i := 0; i < n; i++ {
+		_ = we.New(errors.New("observe"), "failed")
+	}
+
+	if c := testutil.ToFloat64(c.levels); c != float64(n) {
+		t.Errorf("Expected %d but received %f.\n", n, c)
+	}
+
+	if n := testutil.CollectAndCount(c, "wrappederror_errors_by_fingerprint_total"); n != 1 {
+		t.Errorf("Expected 1 but received %d.\n", n)
+	}
+}
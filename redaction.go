@@ -0,0 +1,420 @@
+package wrappederror
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// Struct tag constants.
+const (
+	// The key of the struct tags read by this package.
+	redactionTagKey = "we"
+
+	// The struct tag option that marks a field as redacted.
+	redactionTagOption = "redact"
+)
+
+// The maximum number of nested values that are examined when redacting a
+// context. Values nested any deeper are output as they are.
+const redactionMaxDepth = 32
+
+// Redactor types return a copy of themselves that is safe to output.
+//
+// When an error's context, or a value nested in its context, implements
+// Redactor, the value returned by Redact is output instead. Nested values are
+// replaced only if the returned value can be assigned to the nested value's
+// type, and are replaced with their zero value otherwise.
+type Redactor interface {
+
+	// Redact returns a copy of the receiver with its sensitive values removed.
+	Redact() interface{}
+}
+
+// The reflected type of the Redactor interface.
+var redactorType = reflect.TypeOf((*Redactor)(nil)).Elem()
+
+// A cache of whether or not values of a type may need to be redacted.
+var redactionTypeCache sync.Map
+
+// redactionPointer types identify the value that a pointer points to.
+type redactionPointer struct {
+	t reflect.Type
+	p uintptr
+}
+
+// redaction types redact a single context.
+type redaction struct {
+
+	// The string that replaces redacted strings.
+	mask string
+
+	// The redacted copies of the pointers that have been redacted.
+	copies map[redactionPointer]reflect.Value
+}
+
+// Initializers
+
+// newRedaction creates and returns a new redaction with the given mask.
+func newRedaction(mask string) *redaction {
+	return &redaction{
+		mask:   mask,
+		copies: make(map[redactionPointer]reflect.Value),
+	}
+}
+
+// Non-exported methods
+
+// needed returns whether or not the value, v, at the given nesting depth
+// contains a value that needs to be redacted. Pointers in visiting are being
+// examined and are assumed not to need redaction.
+//
+// The value is only read, and only through exported struct fields.
+func (r *redaction) needed(
+	v reflect.Value,
+	depth int,
+	visiting map[redactionPointer]bool,
+) bool {
+	t := v.Type()
+	if depth > redactionMaxDepth || !redactionNeeded(t) {
+		return false
+	}
+
+	if t.Implements(redactorType) && v.Kind() != reflect.Interface {
+		return !redactionNil(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		return !v.IsNil() && r.needed(v.Elem(), depth+1, visiting)
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			return false
+		}
+
+		p := redactionPointer{t: t, p: v.Pointer()}
+		if _, ok := r.copies[p]; ok {
+			return true
+		}
+
+		if visiting[p] {
+			return false
+		}
+		visiting[p] = true
+
+		return r.needed(v.Elem(), depth+1, visiting)
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if r.needed(v.Index(i), depth+1, visiting) {
+				return true
+			}
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if r.needed(iter.Value(), depth+1, visiting) {
+				return true
+			}
+		}
+
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if redactionTagged(f) {
+				return true
+			}
+
+			if f.IsExported() && r.needed(v.Field(i), depth+1, visiting) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// value returns a redacted copy of the value, v, at the given nesting depth.
+// Values that don't need to be redacted are returned as they are.
+func (r *redaction) value(v reflect.Value, depth int) reflect.Value {
+	t := v.Type()
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		// Pointers that have already been redacted share their copy so that
+		// cycles are preserved.
+		if c, ok := r.copies[redactionPointer{t: t, p: v.Pointer()}]; ok {
+			return c
+		}
+	}
+
+	if !r.needed(v, depth, make(map[redactionPointer]bool)) {
+		return v
+	}
+
+	if t.Implements(redactorType) && v.Kind() != reflect.Interface {
+		rv := reflect.ValueOf(v.Interface().(Redactor).Redact())
+		if rv.IsValid() && rv.Type().AssignableTo(t) {
+			c := reflect.New(t).Elem()
+			c.Set(rv)
+			return c
+		}
+		return reflect.Zero(t)
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		c := reflect.New(t).Elem()
+		c.Set(r.value(v.Elem(), depth+1))
+		return c
+
+	case reflect.Ptr:
+		c := reflect.New(t.Elem())
+		r.copies[redactionPointer{t: t, p: v.Pointer()}] = c
+		c.Elem().Set(r.value(v.Elem(), depth+1))
+		return c
+
+	case reflect.Slice:
+		c := reflect.MakeSlice(t, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(r.value(v.Index(i), depth+1))
+		}
+		return c
+
+	case reflect.Array:
+		c := reflect.New(t).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(r.value(v.Index(i), depth+1))
+		}
+		return c
+
+	case reflect.Map:
+		c := reflect.MakeMapWithSize(t, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), r.value(iter.Value(), depth+1))
+		}
+		return c
+
+	case reflect.Struct:
+		c := reflect.New(t).Elem()
+		c.Set(v)
+
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if redactionTagged(f) {
+				redactionSetField(c, i, redactionMask(f.Type, r.mask))
+			} else if f.IsExported() {
+				c.Field(i).Set(r.value(v.Field(i), depth+1))
+			}
+		}
+		return c
+	}
+
+	return v
+}
+
+// Non-exported functions
+
+// redactContext returns a copy of the context, ctx, that is safe to output.
+//
+// Non-nil contexts that implement Redactor are replaced with the value
+// returned by their Redact method. Struct fields with the `we:"redact"` tag are
+// replaced with the redaction mask if they are strings, and with their zero
+// value otherwise. Structs nested in pointers, slices, arrays, maps, interfaces and
+// exported struct fields are redacted recursively.
+//
+// Values are only copied if they contain a value that needs to be redacted, and
+// unexported struct fields are never examined.
+func redactContext(ctx interface{}) interface{} {
+	if ctx == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(ctx)
+	if r, ok := ctx.(Redactor); ok {
+		if redactionNil(v) {
+			return ctx
+		}
+		return r.Redact()
+	}
+
+	if !redactionNeeded(v.Type()) {
+		return ctx
+	}
+
+	r := newRedaction(packageState.config.RedactionMask())
+	if !r.needed(v, 0, make(map[redactionPointer]bool)) {
+		return ctx
+	}
+
+	return r.value(v, 0).Interface()
+}
+
+// redactString replaces the matches of the configured redaction patterns in s
+// with the redaction mask.
+func redactString(s string) string {
+	patterns := packageState.config.redactionPatterns.get().([]*regexp.Regexp)
+	if len(patterns) == 0 || s == "" {
+		return s
+	}
+
+	mask := packageState.config.RedactionMask()
+	for _, p := range patterns {
+		s = redactMatches(s, p, mask)
+	}
+
+	return s
+}
+
+// redactJSON replaces the matches of the configured redaction patterns in the
+// string values of the JSON data.
+func redactJSON(data []byte) ([]byte, error) {
+	patterns := packageState.config.redactionPatterns.get().([]*regexp.Regexp)
+	if len(patterns) == 0 {
+		return data, nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	n, err := decodeEncoderNode(d)
+	if err != nil {
+		return nil, err
+	}
+
+	n.redact()
+	return json.Marshal(n)
+}
+
+// redactMatches replaces each match of the pattern, p, in s with mask. If p has
+// subexpressions, then only the text they match is replaced.
+func redactMatches(s string, p *regexp.Regexp, mask string) string {
+	if p.NumSubexp() == 0 {
+		return p.ReplaceAllLiteralString(s, mask)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range p.FindAllStringSubmatchIndex(s, -1) {
+		for i := 2; i < len(m); i += 2 {
+			if m[i] < last || m[i] == m[i+1] {
+				continue
+			}
+
+			b.WriteString(s[last:m[i]])
+			b.WriteString(mask)
+			last = m[i+1]
+		}
+	}
+
+	if last == 0 {
+		return s
+	}
+
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// redactionNeeded returns whether or not values of the type, t, may contain a
+// value that needs to be redacted.
+func redactionNeeded(t reflect.Type) bool {
+	if n, ok := redactionTypeCache.Load(t); ok {
+		return n.(bool)
+	}
+
+	n := redactionNeededVisiting(t, make(map[reflect.Type]bool))
+	redactionTypeCache.Store(t, n)
+	return n
+}
+
+// redactionNeededVisiting returns whether or not values of the type, t, may
+// contain a value that needs to be redacted. Types in visiting are being
+// examined and are assumed not to need redaction.
+//
+// Interfaces may contain any value, so they are examined when they are
+// redacted.
+func redactionNeededVisiting(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if n, ok := redactionTypeCache.Load(t); ok {
+		return n.(bool)
+	}
+
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+
+	if t.Implements(redactorType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return redactionNeededVisiting(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if redactionTagged(f) {
+				return true
+			}
+
+			if f.IsExported() && redactionNeededVisiting(f.Type, visiting) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// redactionTagged returns whether or not the struct field, f, has the
+// `we:"redact"` tag.
+func redactionTagged(f reflect.StructField) bool {
+	for _, o := range strings.Split(f.Tag.Get(redactionTagKey), ",") {
+		if strings.TrimSpace(o) == redactionTagOption {
+			return true
+		}
+	}
+	return false
+}
+
+// redactionMask returns the value that replaces a redacted value of type t.
+// Strings are replaced with the mask, and all other values with their zero
+// value.
+func redactionMask(t reflect.Type, mask string) reflect.Value {
+	if t.Kind() != reflect.String {
+		return reflect.Zero(t)
+	}
+
+	v := reflect.New(t).Elem()
+	v.SetString(mask)
+	return v
+}
+
+// redactionNil returns whether or not the value, v, is a nil pointer, map or
+// slice.
+func redactionNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// redactionSetField sets the field with index i of the addressable struct
+// copy, c, to the value, v.
+//
+// Unexported fields are written through their address in the copy. Only masks
+// are written this way, and the original struct is never read through it.
+func redactionSetField(c reflect.Value, i int, v reflect.Value) {
+	f := c.Field(i)
+	if !f.CanSet() {
+		f = reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+	}
+	f.Set(v)
+}
@@ -0,0 +1,406 @@
+package wrappederror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testRedactionCredentials struct {
+	User     string
+	Password string `we:"redact"`
+	pin      int    `we:"redact"`
+	token    string `we:"redact,other"`
+}
+
+type testRedactionContext struct {
+	Credentials *testRedactionCredentials
+	List        []testRedactionCredentials
+	Map         map[string]testRedactionCredentials
+	Any         interface{}
+	Key         testRedactionKey
+}
+
+type testRedactionKey struct {
+	Value string
+}
+
+func (k testRedactionKey) Redact() interface{} {
+	return testRedactionKey{Value: k.Value[:2] + "..."}
+}
+
+type testRedactionSecret string
+
+func (s testRedactionSecret) Redact() interface{} {
+	return "secret"
+}
+
+type testRedactionRequest struct {
+	Token string
+}
+
+func (r *testRedactionRequest) Redact() interface{} {
+	return &testRedactionRequest{Token: "..."}
+}
+
+type testRedactionNode struct {
+	Password string `we:"redact"`
+	Next     *testRedactionNode
+}
+
+// The password of the error used to verify output paths.
+var testRedactionPassword = "hunter2"
+
+// The error used to verify output paths. Its source fragment contains the
+// token on the line above the call to New.
+func testRedactionError() *Error {
+	c := testRedactionCredentials{User: "bob", Password: testRedactionPassword}
+	token := "sk_live_abc123"
+	return New(fmt.Errorf("auth failed with %s", token), c)
+}
+
+func TestRedactContext(t *testing.T) {
+	c := testRedactionCredentials{
+		User:     "bob",
+		Password: "hunter2",
+		pin:      1234,
+		token:    "abc",
+	}
+
+	t.Run("Redact context 0", func(t *testing.T) {
+		testRedactContext(t, c, "{User:bob Password:[REDACTED] pin:0 token:[REDACTED]}")
+	})
+	t.Run("Redact context 1", func(t *testing.T) {
+		testRedactContext(t, &c, "&{User:bob Password:[REDACTED] pin:0 token:[REDACTED]}")
+	})
+	t.Run("Redact context 2", func(t *testing.T) {
+		testRedactContext(t, testRedactionKey{Value: "key_1234"}, "{Value:ke...}")
+	})
+	t.Run("Redact context 3", func(t *testing.T) {
+		testRedactContext(t, testRedactionSecret("hunter2"), "secret")
+	})
+	t.Run("Redact context 4", func(t *testing.T) {
+		testRedactContext(t, "hunter2", "hunter2")
+	})
+	t.Run("Redact context 5", func(t *testing.T) {
+		testRedactContext(t, nil, "<nil>")
+	})
+	t.Run("Redact context 6", func(t *testing.T) {
+		testRedactContext(
+			t,
+			[]interface{}{c, testRedactionSecret("hunter2")},
+			"[{User:bob Password:[REDACTED] pin:0 token:[REDACTED]} ]",
+		)
+	})
+
+	if c.Password != "hunter2" || c.pin != 1234 || c.token != "abc" {
+		t.Errorf("Expected the context to be unmodified but received %+v.\n", c)
+	}
+}
+
+func testRedactContext(t *testing.T, ctx interface{}, e string) {
+	if s := fmt.Sprintf("%+v", redactContext(ctx)); s != e {
+		t.Errorf("Expected \"%s\" but received \"%s\".\n", e, s)
+	}
+}
+
+func TestRedactContextNested(t *testing.T) {
+	c := testRedactionCredentials{User: "bob", Password: "hunter2"}
+	ctx := testRedactionContext{
+		Credentials: &c,
+		List:        []testRedactionCredentials{c},
+		Map:         map[string]testRedactionCredentials{"bob": c},
+		Any:         c,
+		Key:         testRedactionKey{Value: "key_1234"},
+	}
+
+	data, err := json.Marshal(redactContext(ctx))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	s := string(data)
+	if strings.Contains(s, "hunter2") || strings.Contains(s, "key_1234") {
+		t.Errorf("Unexpected secret in %s.\n", s)
+	}
+
+	if strings.Count(s, "[REDACTED]") != 4 {
+		t.Errorf("Expected 4 redacted values in %s.\n", s)
+	}
+
+	if ctx.Credentials.Password != "hunter2" || ctx.List[0].Password != "hunter2" {
+		t.Error("Expected the context to be unmodified.")
+	}
+}
+
+func TestRedactContextRecursive(t *testing.T) {
+	n := &testRedactionNode{Password: "hunter2"}
+	n.Next = n
+
+	r := redactContext(n).(*testRedactionNode)
+	for i := 0; i < 8; i++ {
+		if r.Password != configDefaultRedactionMask {
+			t.Fatalf("Expected node %d to be redacted.\n", i)
+		}
+		r = r.Next
+	}
+
+	if n.Password != "hunter2" || n.Next != n {
+		t.Error("Expected the context to be unmodified.")
+	}
+}
+
+func TestRedactContextNilRedactor(t *testing.T) {
+	var r *testRedactionRequest
+	if c := redactContext(r); c != r {
+		t.Errorf("Expected %v but received %v.\n", r, c)
+	}
+
+	e := New(errors.New("e"), r)
+	if s := e.Error(); s != "<nil>: e" {
+		t.Errorf("Expected <nil>: e but received %s.\n", s)
+	}
+}
+
+func TestRedactContextUnchanged(t *testing.T) {
+	type public struct {
+		Any interface{}
+	}
+
+	type private struct {
+		creds testRedactionCredentials
+	}
+
+	t.Run("Redact context unchanged 0", func(t *testing.T) {
+		ctx := &public{Any: "hunter2"}
+		if r := redactContext(ctx); r != ctx {
+			t.Errorf("Expected %p but received %p.\n", ctx, r)
+		}
+	})
+	t.Run("Redact context unchanged 1", func(t *testing.T) {
+		ctx := &private{creds: testRedactionCredentials{Password: "hunter2"}}
+		if r := redactContext(ctx); r != ctx {
+			t.Errorf("Expected %p but received %p.\n", ctx, r)
+		}
+	})
+}
+
+func TestRedactContextMaxDepth(t *testing.T) {
+	var n *testRedactionNode
+	for i := 0; i <= redactionMaxDepth; i++ {
+		n = &testRedactionNode{Password: "hunter2", Next: n}
+	}
+
+	r := redactContext(n).(*testRedactionNode)
+	if r.Password != configDefaultRedactionMask {
+		t.Error("Expected the first node to be redacted.")
+	}
+
+	for r.Next != nil {
+		r = r.Next
+	}
+
+	if r.Password != "hunter2" {
+		t.Errorf("Expected the last node to be output as it is but received %+v.\n", r)
+	}
+}
+
+func TestRedactContextConcurrentRequest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	e := New(errors.New("boom"), req)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cancel()
+	}()
+
+	_ = e.Error()
+	_ = e.Trace()
+	wg.Wait()
+
+	if redactContext(req) != req {
+		t.Error("Expected the request to be output as it is.")
+	}
+}
+
+func TestRedactString(t *testing.T) {
+	defer Config().SetRedactionPatterns()
+	Config().SetRedactionPatterns(
+		regexp.MustCompile(`sk_live_[a-z0-9]+`),
+		regexp.MustCompile(`password=(\S+)`),
+		regexp.MustCompile(`(a)?(b)`),
+	)
+
+	t.Run("Redact string 0", func(t *testing.T) {
+		testRedactString(t, "token sk_live_abc123 used", "token [REDACTED] used")
+	})
+	t.Run("Redact string 1", func(t *testing.T) {
+		testRedactString(t, "password=hunter2 user=joe", "password=[REDACTED] user=joe")
+	})
+	t.Run("Redact string 2", func(t *testing.T) {
+		testRedactString(t, "xab", "x[REDACTED][REDACTED]")
+	})
+	t.Run("Redact string 3", func(t *testing.T) {
+		testRedactString(t, "none", "none")
+	})
+
+	Config().SetRedactionMask("***")
+	defer Config().SetRedactionMask(configDefaultRedactionMask)
+	t.Run("Redact string 4", func(t *testing.T) {
+		testRedactString(t, "password=hunter2", "password=***")
+	})
+}
+
+func testRedactString(t *testing.T, s string, e string) {
+	if r := redactString(s); r != e {
+		t.Errorf("Expected \"%s\" but received \"%s\".\n", e, r)
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	data := []byte(`{"b":"password=hunter2","a":["x",{"password=hunter2":1}]}`)
+
+	r, err := redactJSON(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if string(r) != string(data) {
+		t.Errorf("Expected %s but received %s.\n", data, r)
+	}
+
+	defer Config().SetRedactionPatterns()
+	Config().SetRedactionPatterns(regexp.MustCompile(`password=(\S+)`))
+
+	if r, err = redactJSON(data); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	e := `{"b":"password=[REDACTED]","a":["x",{"password=hunter2":1}]}`
+	if string(r) != e {
+		t.Errorf("Expected %s but received %s.\n", e, r)
+	}
+
+	if _, err := redactJSON([]byte("{")); err == nil {
+		t.Error("Expected error.")
+	}
+}
+
+func TestErrorRedaction(t *testing.T) {
+	defer Config().SetRedactionPatterns()
+	Config().SetRedactionPatterns(regexp.MustCompile(`sk_live_[a-z0-9]+`))
+
+	e := New(testRedactionError(), "login failed")
+
+	f := e.Unwrap().(*Error).Caller.Fragment()
+	if f == nil || !strings.Contains(f.Source, configDefaultRedactionMask) {
+		t.Fatalf("Expected a redacted source fragment but received %+v.\n", f)
+	}
+
+	for name, s := range testRedactionOutputs(t, e) {
+		if strings.Contains(s, "hunter2") || strings.Contains(s, "sk_live_abc123") {
+			t.Errorf("Unexpected secret in %s output:\n%s\n", name, s)
+		}
+	}
+
+	if e.Unwrap().(*Error).Context().(testRedactionCredentials).Password != "hunter2" {
+		t.Error("Expected the context to be unmodified.")
+	}
+}
+
+func TestFragmentRedactionPatternsChanged(t *testing.T) {
+	defer Config().SetRedactionPatterns()
+
+	c := testRedactionError().Caller
+	if f := c.Fragment(); f == nil || !strings.Contains(f.Source, "sk_live_abc123") {
+		t.Fatalf("Expected an unredacted source fragment but received %+v.\n", f)
+	}
+
+	Config().SetRedactionPatterns(regexp.MustCompile(`sk_live_[a-z0-9]+`))
+	if f := c.Fragment(); f == nil || strings.Contains(f.Source, "sk_live_abc123") {
+		t.Errorf("Expected a redacted source fragment but received %+v.\n", f)
+	}
+
+	Config().SetRedactionPatterns()
+	if f := c.Fragment(); f == nil || !strings.Contains(f.Source, "sk_live_abc123") {
+		t.Errorf("Expected an unredacted source fragment but received %+v.\n", f)
+	}
+}
+
+func testRedactionOutputs(t *testing.T, e *Error) map[string]string {
+	o := map[string]string{
+		"Error": e.Error(),
+		"Trace": e.Trace(),
+		"FormatString": e.FormatString(strings.Join([]string{
+			string(ErrorFormatTokenContext),
+			string(ErrorFormatTokenInner),
+			string(ErrorFormatTokenChain),
+			string(ErrorFormatTokenTrace),
+			string(ErrorFormatTokenSource),
+		}, " ")),
+		"%v":  fmt.Sprintf("%v", e),
+		"%s":  fmt.Sprintf("%s", e),
+		"%q":  fmt.Sprintf("%q", e),
+		"%+v": fmt.Sprintf("%+v", e),
+		"%#v": fmt.Sprintf("%#v", e),
+	}
+
+	o["JSON minimal"] = testRedactionJSON(t, e)
+
+	defer Config().SetMarshalMinimalJSON(configDefaultMarshalMinimalJSON)
+	Config().SetJSONFieldSet(JSONFieldSetFull)
+	o["JSON full"] = testRedactionJSON(t, e)
+
+	data, err := e.MarshalJSONWith(JSONFieldSetFull)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	o["MarshalJSONWith"] = string(data)
+
+	for _, name := range []string{
+		EncoderNameJSON,
+		EncoderNameLogfmt,
+		EncoderNameText,
+		EncoderNameCBOR,
+	} {
+		var b bytes.Buffer
+		if err := e.Encode(&b, name); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+		o[name] = b.String()
+	}
+
+	var b bytes.Buffer
+	slog.New(slog.NewTextHandler(&b, nil)).Error("failed", "err", e)
+	o["slog"] = b.String()
+
+	b.Reset()
+	slog.New(NewSlogHandlerWith(
+		slog.NewJSONHandler(&b, nil),
+		JSONFieldSetFull,
+	)).Error("failed", "err", e)
+	o["SlogHandler"] = b.String()
+
+	return o
+}
+
+func testRedactionJSON(t *testing.T, e *Error) string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,28 @@
+package wrappederror
+
+// RemoteError types represent errors not defined by this package that were
+// unmarshaled from JSON data.
+//
+// Because the original error's type can't be recovered, remote errors only
+// preserve the original error's message and the error it wrapped.
+type RemoteError struct {
+
+	// The output of the original error's Error method.
+	Message string
+
+	// The error that the original error wrapped.
+	inner error
+}
+
+// Exported methods
+
+// Unwrap returns the wrapped error or nil if one doesn't exist.
+func (e RemoteError) Unwrap() error {
+	return e.inner
+}
+
+// Error interface methods
+
+func (e RemoteError) Error() string {
+	return e.Message
+}
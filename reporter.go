@@ -0,0 +1,97 @@
+package wrappederror
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrReporterAlreadyRegistered indicates that a reporter has already been
+// registered with a name.
+var ErrReporterAlreadyRegistered = errors.New("reporter already registered")
+
+// Reporter types receive the errors created by the package.
+//
+// Reporters are registered with the package using the RegisterReporter
+// function. Each reporter receives errors in the order that they were created
+// from its own goroutine, so a reporter that is slow to return only delays its
+// own errors.
+type Reporter interface {
+
+	// Report reports the error, e. Errors returned from Report are counted in
+	// the reporter's statistics.
+	Report(e Error) error
+}
+
+// ReporterFunc types are functions that implement the Reporter interface.
+type ReporterFunc func(e Error) error
+
+// Report calls f(e).
+func (f ReporterFunc) Report(e Error) error {
+	return f(e)
+}
+
+// ChannelReporter types report errors by sending them to a channel.
+//
+// Sending blocks until the channel is received from, so use a buffered channel
+// if errors should be queued rather than dropped when the reporter's queue is
+// full.
+type ChannelReporter chan<- Error
+
+// Report sends the error, e, to the channel.
+func (r ChannelReporter) Report(e Error) error {
+	r <- e
+	return nil
+}
+
+// WriterReporter types report errors by encoding them to a writer.
+type WriterReporter struct {
+	w       io.Writer
+	encoder Encoder
+	mutex   *sync.Mutex
+}
+
+// ReporterStat types describe the errors that have been delivered to a
+// reporter.
+type ReporterStat struct {
+
+	// The number of errors that the reporter reported without an error.
+	Reported uint64 `json:"reported"`
+
+	// The number of errors that weren't delivered to the reporter because its
+	// queue was full.
+	Dropped uint64 `json:"dropped"`
+
+	// The number of errors that the reporter returned an error or panicked
+	// while reporting.
+	Failed uint64 `json:"failed"`
+}
+
+// Initializers
+
+// NewWriterReporter creates and returns a new reporter that encodes errors to
+// the writer, w, with the encoder. If the encoder is nil, then errors are
+// encoded with a JSONEncoder.
+//
+// Errors are encoded one at a time, so the writer doesn't need to be safe for
+// concurrent use.
+func NewWriterReporter(w io.Writer, encoder Encoder) *WriterReporter {
+	if encoder == nil {
+		encoder = JSONEncoder{}
+	}
+
+	return &WriterReporter{
+		w:       w,
+		encoder: encoder,
+		mutex:   new(sync.Mutex),
+	}
+}
+
+// Exported methods
+
+// Report encodes the error, e, to the reporter's writer.
+func (r *WriterReporter) Report(e Error) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.encoder.Encode(r.w, e)
+}
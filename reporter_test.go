@@ -0,0 +1,107 @@
+package wrappederror
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestReporterFunc(t *testing.T) {
+	var reported Error
+	r := ReporterFunc(func(e Error) error {
+		reported = e
+		return nil
+	})
+
+	e := New(errors.New("reporter func"), "failed")
+	if err := r.Report(*e); err != nil {
+		t.Errorf("Unexpected error: %s\n", err)
+	}
+	if reported.Error() != e.Error() {
+		t.Errorf("Expected %s but received %s.\n", e, reported.Error())
+	}
+}
+
+func TestWriterReporter(t *testing.T) {
+	t.Run("Writer reporter 0", func(t *testing.T) {
+		testWriterReporter(t, nil, `"context":"failed"`)
+	})
+	t.Run("Writer reporter 1", func(t *testing.T) {
+		testWriterReporter(t, TextEncoder{}, "context: failed")
+	})
+}
+
+func testWriterReporter(t *testing.T, encoder Encoder, s string) {
+	b := new(bytes.Buffer)
+	r := NewWriterReporter(b, encoder)
+
+	if err := r.Report(*New(errors.New("writer reporter"), "failed")); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	if !strings.Contains(b.String(), s) {
+		t.Errorf("Expected %s in %s.\n", s, b.String())
+	}
+}
+
+func TestRegisterReporter(t *testing.T) {
+	defer UnregisterReporter("test")
+
+	c := make(chan Error, 1)
+	if err := RegisterReporter("test", ChannelReporter(c), ErrorSeverityLevelNone); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if err := RegisterReporter("test", ChannelReporter(c), ErrorSeverityLevelNone); err != ErrReporterAlreadyRegistered {
+		t.Errorf("Expected %s but received %v.\n", ErrReporterAlreadyRegistered, err)
+	}
+
+	e := New(errors.New("registered reporter"), "failed")
+	if err := Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	r := <-c
+	if r.Metadata == nil || r.Metadata.Index != e.Metadata.Index {
+		t.Errorf("Expected error %d to be reported.\n", e.Metadata.Index)
+	}
+
+	s, ok := ReporterStats("test")
+	if !ok {
+		t.Fatal("Expected reporter statistics.")
+	}
+	if s.Reported != 1 {
+		t.Errorf("Expected 1 but received %d.\n", s.Reported)
+	}
+}
+
+func TestRegisterReporterOwnErrors(t *testing.T) {
+	defer UnregisterReporter("test")
+
+	var n atomic.Int64
+	r := ReporterFunc(func(e Error) error {
+		n.Add(1)
+		return NewWithOptions(errors.New("report failed"), "reporter", WithoutReporting())
+	})
+	if err := RegisterReporter("test", r, ErrorSeverityLevelNone); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	New(errors.New("own errors"), "failed")
+	for i := 0; i < 2; i++ {
+		if err := Flush(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+	}
+
+	if n.Load() != 1 {
+		t.Errorf("Expected 1 but received %d.\n", n.Load())
+	}
+
+	s, _ := ReporterStats("test")
+	if s.Failed != 1 {
+		t.Errorf("Expected 1 but received %d.\n", s.Failed)
+	}
+}
@@ -0,0 +1,289 @@
+package wrappederror
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// reporterTable keeps track of named reporters and their queues.
+type reporterTable struct {
+	queues map[string]*reporterQueue
+
+	// The queues of unregistered reporters that may still contain errors.
+	draining map[*reporterQueue]struct{}
+
+	// Mutex for safe access to queues and draining.
+	queuesMutex *sync.RWMutex
+}
+
+// reporterQueue types deliver errors to a reporter from a bounded queue.
+type reporterQueue struct {
+	reporter Reporter
+
+	// The minimum severity level of the errors delivered to the reporter.
+	min ErrorSeverityLevel
+
+	// The queued errors.
+	errors chan Error
+
+	// The number of errors that have been queued but not yet reported.
+	pending int
+
+	// A channel that is closed when there are no pending errors.
+	idle chan struct{}
+
+	// Whether or not the queue has been closed.
+	closed bool
+
+	// A channel that is closed once the queue has been closed and every error
+	// in it has been reported.
+	done chan struct{}
+
+	// Mutex for safe access to pending, idle and closed.
+	mutex *sync.Mutex
+
+	reported atomic.Uint64
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+}
+
+// Initializers
+
+// newReporterTable creates and returns a new, empty reporter table.
+func newReporterTable() *reporterTable {
+	return &reporterTable{
+		queues:      make(map[string]*reporterQueue),
+		draining:    make(map[*reporterQueue]struct{}),
+		queuesMutex: new(sync.RWMutex),
+	}
+}
+
+// newReporterQueue creates and returns a new queue of the given size that
+// delivers errors with at least the severity level, min, to the reporter, and
+// starts delivering them.
+func newReporterQueue(
+	reporter Reporter,
+	min ErrorSeverityLevel,
+	size int,
+) *reporterQueue {
+	if size < 1 {
+		size = 1
+	}
+
+	idle := make(chan struct{})
+	close(idle)
+
+	q := &reporterQueue{
+		reporter: reporter,
+		min:      min,
+		errors:   make(chan Error, size),
+		idle:     idle,
+		done:     make(chan struct{}),
+		mutex:    new(sync.Mutex),
+	}
+
+	go q.run()
+	return q
+}
+
+// Methods
+
+// register registers the reporter with the given name and starts delivering
+// errors with at least the severity level, min, to it through a queue of the
+// given size. If a reporter has already been registered with the name, then it
+// returns an ErrReporterAlreadyRegistered error.
+func (t *reporterTable) register(
+	name string,
+	reporter Reporter,
+	min ErrorSeverityLevel,
+	size int,
+) error {
+	t.queuesMutex.Lock()
+	defer t.queuesMutex.Unlock()
+
+	if _, ok := t.queues[name]; ok {
+		return ErrReporterAlreadyRegistered
+	}
+
+	t.queues[name] = newReporterQueue(reporter, min, size)
+	return nil
+}
+
+// unregister unregisters the reporter with the given name. Errors that have
+// already been queued for the reporter are still delivered to it, and are
+// waited for by flush.
+func (t *reporterTable) unregister(name string) {
+	t.queuesMutex.Lock()
+	defer t.queuesMutex.Unlock()
+
+	if q, ok := t.queues[name]; ok {
+		t.drain(q)
+		delete(t.queues, name)
+	}
+}
+
+// report queues the error, e, for each reporter with a minimum severity level
+// that the error's level is at least.
+func (t *reporterTable) report(e Error) {
+	t.queuesMutex.RLock()
+	defer t.queuesMutex.RUnlock()
+
+	if len(t.queues) == 0 {
+		return
+	}
+
+	level := ErrorSeverityLevelNone
+	if e.Metadata != nil && e.Metadata.Severity != nil {
+		level = e.Metadata.Severity.Level
+	}
+
+	for _, q := range t.queues {
+		if level.AtLeast(q.min) {
+			q.enqueue(e)
+		}
+	}
+}
+
+// flush blocks until every error queued for the table's reporters, including
+// reporters that have been unregistered, has been reported, or the context is
+// done.
+func (t *reporterTable) flush(ctx context.Context) error {
+	t.queuesMutex.RLock()
+	idle := make([]chan struct{}, 0, len(t.queues)+len(t.draining))
+	for _, q := range t.queues {
+		idle = append(idle, q.idleChannel())
+	}
+	for q := range t.draining {
+		idle = append(idle, q.idleChannel())
+	}
+	t.queuesMutex.RUnlock()
+
+	for _, c := range idle {
+		select {
+		case <-c:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// stats returns the statistics of the reporter with the given name, and
+// whether or not it exists.
+func (t *reporterTable) stats(name string) (ReporterStat, bool) {
+	t.queuesMutex.RLock()
+	defer t.queuesMutex.RUnlock()
+
+	q, ok := t.queues[name]
+	if !ok {
+		return ReporterStat{}, false
+	}
+
+	return ReporterStat{
+		Reported: q.reported.Load(),
+		Dropped:  q.dropped.Load(),
+		Failed:   q.failed.Load(),
+	}, true
+}
+
+// close unregisters every reporter in the table.
+func (t *reporterTable) close() {
+	t.queuesMutex.Lock()
+	defer t.queuesMutex.Unlock()
+
+	for name, q := range t.queues {
+		t.drain(q)
+		delete(t.queues, name)
+	}
+}
+
+// drain closes the queue, q, and keeps track of it until its errors have been
+// reported. The caller must hold the table's write lock.
+func (t *reporterTable) drain(q *reporterQueue) {
+	for d := range t.draining {
+		select {
+		case <-d.done:
+			delete(t.draining, d)
+		default:
+		}
+	}
+
+	q.close()
+	t.draining[q] = struct{}{}
+}
+
+// enqueue adds the error, e, to the queue without blocking. If the queue is
+// full, then the error is dropped.
+func (q *reporterQueue) enqueue(e Error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	select {
+	case q.errors <- e:
+		if q.pending == 0 {
+			q.idle = make(chan struct{})
+		}
+		q.pending++
+	default:
+		q.dropped.Add(1)
+	}
+}
+
+// idleChannel returns a channel that is closed once the errors that are
+// currently pending have been reported.
+func (q *reporterQueue) idleChannel() chan struct{} {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.idle
+}
+
+// close stops the queue from accepting errors. The queue's goroutine returns
+// once the errors that are already queued have been reported.
+func (q *reporterQueue) close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		close(q.errors)
+	}
+}
+
+// run delivers the queued errors to the queue's reporter until the queue is
+// closed.
+func (q *reporterQueue) run() {
+	defer close(q.done)
+
+	for e := range q.errors {
+		if err := q.deliver(e); err != nil {
+			q.failed.Add(1)
+		} else {
+			q.reported.Add(1)
+		}
+
+		q.mutex.Lock()
+		q.pending--
+		if q.pending == 0 {
+			close(q.idle)
+		}
+		q.mutex.Unlock()
+	}
+}
+
+// deliver reports the error, e, and recovers from panics in the reporter.
+func (q *reporterQueue) deliver(e Error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("reporter panicked: %v", v)
+		}
+	}()
+
+	return q.reporter.Report(e)
+}
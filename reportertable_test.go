@@ -0,0 +1,192 @@
+package wrappederror
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReporterTableRegister(t *testing.T) {
+	rt := newReporterTable()
+	defer rt.close()
+
+	r := ReporterFunc(func(e Error) error { return nil })
+	if err := rt.register("test", r, ErrorSeverityLevelNone, 1); err != nil {
+		t.Errorf("Unexpected error: %s\n", err)
+	}
+	if err := rt.register("test", r, ErrorSeverityLevelNone, 1); err != ErrReporterAlreadyRegistered {
+		t.Errorf("Expected %s but received %v.\n", ErrReporterAlreadyRegistered, err)
+	}
+	if _, ok := rt.stats("test"); !ok {
+		t.Error("Expected reporter statistics.")
+	}
+
+	rt.unregister("test")
+	if _, ok := rt.stats("test"); ok {
+		t.Error("Unexpected reporter statistics.")
+	}
+}
+
+func TestReporterTableReport(t *testing.T) {
+	rt := newReporterTable()
+	defer rt.close()
+
+	c := make(chan Error, 4)
+	if err := rt.register("test", ChannelReporter(c), ErrorSeverityLevelHigh, 4); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	high := &ErrorSeverity{Level: ErrorSeverityLevelHigh}
+	low := &ErrorSeverity{Level: ErrorSeverityLevelLow}
+	rt.report(Error{Metadata: &Metadata{Index: 1, Severity: high}})
+	rt.report(Error{Metadata: &Metadata{Index: 2, Severity: low}})
+	rt.report(Error{Metadata: &Metadata{Index: 3}})
+
+	if err := rt.flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	if len(c) != 1 {
+		t.Fatalf("Expected 1 but received %d.\n", len(c))
+	}
+	if e := <-c; e.Metadata.Index != 1 {
+		t.Errorf("Expected 1 but received %d.\n", e.Metadata.Index)
+	}
+
+	s, _ := rt.stats("test")
+	if s != (ReporterStat{Reported: 1}) {
+		t.Errorf("Unexpected statistics: %+v\n", s)
+	}
+}
+
+func TestReporterTableDrop(t *testing.T) {
+	rt := newReporterTable()
+	defer rt.close()
+
+	block := make(chan struct{})
+	started := make(chan struct{}, 1)
+	r := ReporterFunc(func(e Error) error {
+		started <- struct{}{}
+		<-block
+		return nil
+	})
+	if err := rt.register("test", r, ErrorSeverityLevelNone, 1); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	// The first error is delivered, the second is queued, and the third is
+	// dropped.
+	rt.report(Error{})
+	<-started
+	rt.report(Error{})
+	rt.report(Error{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := rt.flush(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected %s but received %v.\n", context.DeadlineExceeded, err)
+	}
+
+	close(block)
+	<-started
+	if err := rt.flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	s, _ := rt.stats("test")
+	if s != (ReporterStat{Reported: 2, Dropped: 1}) {
+		t.Errorf("Unexpected statistics: %+v\n", s)
+	}
+}
+
+func TestReporterTableFailed(t *testing.T) {
+	rt := newReporterTable()
+	defer rt.close()
+
+	r := ReporterFunc(func(e Error) error {
+		if e.Metadata.Index == 1 {
+			panic("report failed")
+		}
+		return errors.New("report failed")
+	})
+	if err := rt.register("test", r, ErrorSeverityLevelNone, 2); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	rt.report(Error{Metadata: &Metadata{Index: 1}})
+	rt.report(Error{Metadata: &Metadata{Index: 2}})
+	if err := rt.flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	s, _ := rt.stats("test")
+	if s != (ReporterStat{Failed: 2}) {
+		t.Errorf("Unexpected statistics: %+v\n", s)
+	}
+}
+
+func TestReporterTableUnregister(t *testing.T) {
+	rt := newReporterTable()
+
+	c := make(chan Error, 2)
+	if err := rt.register("test", ChannelReporter(c), ErrorSeverityLevelNone, 2); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	rt.report(Error{})
+	rt.report(Error{})
+	rt.close()
+	rt.report(Error{})
+
+	// Queued errors are still delivered after the reporter is unregistered.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-c:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected error %d.\n", i)
+		}
+	}
+
+	select {
+	case <-c:
+		t.Error("Unexpected error.")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestReporterTableFlushUnregistered(t *testing.T) {
+	rt := newReporterTable()
+	defer rt.close()
+
+	block := make(chan struct{})
+	var n atomic.Int64
+	r := ReporterFunc(func(e Error) error {
+		<-block
+		n.Add(1)
+		return nil
+	})
+	if err := rt.register("test", r, ErrorSeverityLevelNone, 2); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	rt.report(Error{})
+	rt.report(Error{})
+	rt.unregister("test")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := rt.flush(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected %s but received %v.\n", context.DeadlineExceeded, err)
+	}
+
+	close(block)
+	if err := rt.flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	if n.Load() != 2 {
+		t.Errorf("Expected 2 but received %d.\n", n.Load())
+	}
+}
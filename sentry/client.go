@@ -0,0 +1,347 @@
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	we "github.com/colinc86/wrappederror"
+)
+
+// ErrUnexpectedStatus indicates that the server responded to an envelope with
+// a status code other than 200.
+var ErrUnexpectedStatus = errors.New("unexpected status")
+
+// The version of the Sentry protocol that the package implements.
+const protocolVersion = 7
+
+// The name of the client that sends events.
+const clientName = "wrappederror-sentry/1.0"
+
+// The content type of envelopes.
+const envelopeContentType = "application/x-sentry-envelope"
+
+// Default values of a client's options.
+const (
+	defaultMaxRetries = 3
+	defaultRetryDelay = 500 * time.Millisecond
+	defaultTimeout    = 30 * time.Second
+)
+
+// The maximum delay between attempts to send an envelope.
+const maxRetryDelay = 30 * time.Second
+
+// Client types convert errors to events and send them to a Sentry server.
+//
+// Clients implement the wrappederror.Reporter interface, so they can be
+// registered with RegisterReporter to send every error that's created.
+type Client struct {
+	dsn     *DSN
+	options options
+}
+
+// Option types set the options of a client.
+type Option func(o *options)
+
+// options types contain a client's options.
+type options struct {
+	httpClient  *http.Client
+	maxRetries  int
+	retryDelay  time.Duration
+	release     string
+	environment string
+	serverName  string
+	tags        map[string]string
+
+	// The package import path prefixes of in-app frames.
+	inAppPackages []string
+}
+
+// Initializers
+
+// NewClient creates and returns a new client that sends events to the project
+// identified by dsn. If the DSN is invalid, then an error that wraps
+// ErrInvalidDSN is returned.
+func NewClient(dsn string, opts ...Option) (*Client, error) {
+	d, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	o := options{
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		maxRetries: defaultMaxRetries,
+		retryDelay: defaultRetryDelay,
+		tags:       make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Client{dsn: d, options: o}, nil
+}
+
+// Options
+
+// WithHTTPClient sets the HTTP client used to send envelopes.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		if c != nil {
+			o.httpClient = c
+		}
+	}
+}
+
+// WithRetries sets the number of times a client retries sending an envelope,
+// and the delay before the first retry. The delay doubles after each retry
+// unless the server responds with a Retry-After header.
+//
+// Envelopes are retried after network errors and responses with the status
+// codes 429 and 5xx.
+func WithRetries(n int, delay time.Duration) Option {
+	return func(o *options) {
+		o.maxRetries = n
+		o.retryDelay = delay
+	}
+}
+
+// WithRelease sets the release of the events that the client creates.
+func WithRelease(release string) Option {
+	return func(o *options) {
+		o.release = release
+	}
+}
+
+// WithEnvironment sets the environment of the events that the client creates.
+func WithEnvironment(environment string) Option {
+	return func(o *options) {
+		o.environment = environment
+	}
+}
+
+// WithServerName sets the server name of the events that the client creates.
+func WithServerName(name string) Option {
+	return func(o *options) {
+		o.serverName = name
+	}
+}
+
+// WithTag adds a tag to the events that the client creates.
+func WithTag(key string, value string) Option {
+	return func(o *options) {
+		o.tags[key] = value
+	}
+}
+
+// WithInAppPackages sets the package import path prefixes of the frames that
+// belong to your application.
+//
+// By default, frames are in-app unless they belong to the standard library, a
+// vendored package or a package in the module cache.
+func WithInAppPackages(prefixes ...string) Option {
+	return func(o *options) {
+		o.inAppPackages = append([]string(nil), prefixes...)
+	}
+}
+
+// Exported methods
+
+// DSN returns the client's DSN.
+func (c *Client) DSN() DSN {
+	return *c.dsn
+}
+
+// Event converts the error, e, to an event.
+//
+// The event's exceptions are the errors in the error's chain. Errors that
+// captured their call information have a stack trace with the lines of their
+// source fragment, and the event's fingerprint is the error's fingerprint so
+// that Sentry groups similar errors together.
+func (c *Client) Event(e we.Error) *Event {
+	return newEvent(e, c.options)
+}
+
+// Send sends the event to the client's server. If the server can't be reached,
+// or it responds with a status code of 429 or 5xx, then the envelope is sent
+// again until the client's retries are exhausted or the context is done.
+func (c *Client) Send(ctx context.Context, ev *Event) error {
+	body, err := newEnvelope(ev, *c.dsn, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	delay := c.options.retryDelay
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.post(ctx, body)
+		if err == nil || attempt >= c.options.maxRetries || !retryable(err) {
+			return err
+		}
+
+		d := delay
+		if retryAfter > 0 {
+			d = retryAfter
+		}
+		if d > maxRetryDelay {
+			d = maxRetryDelay
+		}
+
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+
+		delay *= 2
+	}
+}
+
+// Report converts the error, e, to an event and sends it to the client's
+// server.
+func (c *Client) Report(e we.Error) error {
+	return c.Send(context.Background(), c.Event(e))
+}
+
+// Non-exported methods
+
+// post posts the envelope, body, to the client's server and returns the
+// duration that the server asked to wait before retrying.
+func (c *Client) post(ctx context.Context, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.dsn.EnvelopeURL(),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", envelopeContentType)
+	req.Header.Set("X-Sentry-Auth", c.dsn.AuthHeader())
+
+	resp, err := c.options.httpClient.Do(req)
+	if err != nil {
+		return 0, &sendError{err: err, retryable: ctx.Err() == nil}
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode == http.StatusOK {
+		return 0, nil
+	}
+
+	return retryAfter(resp.Header.Get("Retry-After")), &sendError{
+		err: fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode),
+		retryable: resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode >= http.StatusInternalServerError,
+	}
+}
+
+// inApp returns whether or not the frame, f, belongs to the application.
+func (o options) inApp(f we.Frame) bool {
+	if len(o.inAppPackages) > 0 {
+		for _, p := range o.inAppPackages {
+			if f.Package == p || strings.HasPrefix(f.Package, p+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if strings.Contains(f.File, "/vendor/") || strings.Contains(f.File, "/pkg/mod/") {
+		return false
+	}
+
+	// Standard library packages don't have a domain in their first path
+	// element.
+	root := f.Package
+	if i := strings.Index(root, "/"); i >= 0 {
+		root = root[:i]
+	}
+	return root == "main" || strings.Contains(root, ".")
+}
+
+// sendError types are the errors that occur while sending an envelope.
+type sendError struct {
+	err       error
+	retryable bool
+}
+
+func (e *sendError) Error() string {
+	return e.err.Error()
+}
+
+func (e *sendError) Unwrap() error {
+	return e.err
+}
+
+// Non-exported functions
+
+// newEnvelope creates and returns an envelope that contains the event.
+func newEnvelope(ev *Event, dsn DSN, sentAt time.Time) ([]byte, error) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := json.Marshal(map[string]interface{}{
+		"event_id": ev.EventID,
+		"sent_at":  sentAt.Format(time.RFC3339Nano),
+		"dsn":      dsn.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := json.Marshal(map[string]interface{}{
+		"type":   "event",
+		"length": len(payload),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	for _, line := range [][]byte{header, item, payload} {
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes(), nil
+}
+
+// retryable returns whether or not the envelope should be sent again after
+// the error, err.
+func retryable(err error) bool {
+	var se *sendError
+	return errors.As(err, &se) && se.retryable
+}
+
+// retryAfter returns the duration in the Retry-After header value, v, or 0 if
+// it can't be parsed.
+func retryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	if s, err := strconv.Atoi(v); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,271 @@
+package sentry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	we "github.com/colinc86/wrappederror"
+)
+
+// testServer types are fake Sentry servers that record the envelopes they
+// receive.
+type testServer struct {
+	*httptest.Server
+
+	// The status codes to respond with, in order. Once they've been used, the
+	// server responds with 200.
+	statuses []int
+
+	// The value of the Retry-After header sent with error responses.
+	retryAfter string
+
+	requests []*http.Request
+	events   []Event
+	mutex    sync.Mutex
+}
+
+func newTestServer(t *testing.T, statuses ...int) *testServer {
+	s := &testServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.requests = append(s.requests, r)
+		if len(s.statuses) > 0 {
+			status := s.statuses[0]
+			s.statuses = s.statuses[1:]
+			if s.retryAfter != "" {
+				w.Header().Set("Retry-After", s.retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+
+		ev, err := readTestEnvelope(r.Body)
+		if err != nil {
+			t.Errorf("Unexpected error: %s\n", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		s.events = append(s.events, *ev)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) dsn() string {
+	return strings.Replace(s.URL, "http://", "http://public@", 1) + "/42"
+}
+
+func (s *testServer) received() ([]*http.Request, []Event) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests, s.events
+}
+
+// readTestEnvelope reads an envelope with a single event item.
+func readTestEnvelope(r io.Reader) (*Event, error) {
+	br := bufio.NewReader(r)
+
+	var header struct {
+		EventID string `json:"event_id"`
+		DSN     string `json:"dsn"`
+	}
+	line, err := br.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(line, &header); err != nil {
+		return nil, err
+	}
+
+	var item struct {
+		Type   string `json:"type"`
+		Length int    `json:"length"`
+	}
+	line, err = br.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(line, &item); err != nil {
+		return nil, err
+	}
+	if item.Type != "event" {
+		return nil, errors.New("unexpected item type " + item.Type)
+	}
+
+	payload := make([]byte, item.Length)
+	if _, err := io.ReadFull(br, payload); err != nil {
+		return nil, err
+	}
+
+	var ev Event
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return nil, err
+	}
+	if ev.EventID != header.EventID {
+		return nil, errors.New("mismatched event IDs")
+	}
+
+	return &ev, nil
+}
+
+func TestClientSend(t *testing.T) {
+	s := newTestServer(t)
+	c, err := NewClient(s.dsn())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	ev := c.Event(*we.New(errors.New("send"), "failed"))
+	if err := c.Send(context.Background(), ev); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	reqs, evs := s.received()
+	if len(reqs) != 1 || len(evs) != 1 {
+		t.Fatalf("Expected 1 request but received %d.\n", len(reqs))
+	}
+
+	r := reqs[0]
+	if r.URL.Path != "/api/42/envelope/" {
+		t.Errorf("Unexpected path: %s\n", r.URL.Path)
+	}
+	if ct := r.Header.Get("Content-Type"); ct != envelopeContentType {
+		t.Errorf("Expected %s but received %s.\n", envelopeContentType, ct)
+	}
+	if a := r.Header.Get("X-Sentry-Auth"); !strings.Contains(a, "sentry_key=public") {
+		t.Errorf("Unexpected auth header: %s\n", a)
+	}
+
+	if evs[0].EventID != ev.EventID {
+		t.Errorf("Expected %s but received %s.\n", ev.EventID, evs[0].EventID)
+	}
+	if len(evs[0].Exception.Values) != 2 {
+		t.Errorf("Expected 2 exceptions but received %d.\n", len(evs[0].Exception.Values))
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	t.Run("Client retries 0", func(t *testing.T) {
+		testClientRetries(t, []int{503, 429}, nil, 3)
+	})
+	t.Run("Client retries 1", func(t *testing.T) {
+		testClientRetries(t, []int{400}, ErrUnexpectedStatus, 1)
+	})
+	t.Run("Client retries 2", func(t *testing.T) {
+		testClientRetries(t, []int{500, 500, 500}, ErrUnexpectedStatus, 3)
+	})
+}
+
+func testClientRetries(t *testing.T, statuses []int, e error, requests int) {
+	s := newTestServer(t, statuses...)
+	c, err := NewClient(s.dsn(), WithRetries(2, time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	err = c.Send(context.Background(), c.Event(*we.New(nil, "retries")))
+	if !errors.Is(err, e) {
+		t.Errorf("Expected %v but received %v.\n", e, err)
+	}
+
+	if reqs, _ := s.received(); len(reqs) != requests {
+		t.Errorf("Expected %d requests but received %d.\n", requests, len(reqs))
+	}
+}
+
+func TestClientRetryAfter(t *testing.T) {
+	s := newTestServer(t, http.StatusTooManyRequests)
+	s.retryAfter = "60"
+
+	c, err := NewClient(s.dsn(), WithRetries(1, time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	// The server asks the client to wait longer than the context allows.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err = c.Send(ctx, c.Event(*we.New(nil, "retry after")))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %s but received %v.\n", context.DeadlineExceeded, err)
+	}
+
+	if reqs, _ := s.received(); len(reqs) != 1 {
+		t.Errorf("Expected 1 request but received %d.\n", len(reqs))
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for v, e := range map[string]time.Duration{
+		"":      0,
+		"5":     5 * time.Second,
+		"-1":    0,
+		"later": 0,
+	} {
+		if d := retryAfter(v); d != e {
+			t.Errorf("Expected %s for %q but received %s.\n", e, v, d)
+		}
+	}
+}
+
+func TestClientReport(t *testing.T) {
+	s := newTestServer(t)
+	c, err := NewClient(s.dsn())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	if err := we.RegisterReporter("sentry", c, we.ErrorSeverityLevelNone); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	defer we.UnregisterReporter("sentry")
+
+	e := we.New(errors.New("report"), "failed")
+	if err := we.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	_, evs := s.received()
+	if len(evs) != 1 {
+		t.Fatalf("Expected 1 event but received %d.\n", len(evs))
+	}
+
+	if evs[0].Fingerprint[0] != e.Metadata.Fingerprint {
+		t.Errorf("Expected %s but received %s.\n", e.Metadata.Fingerprint, evs[0].Fingerprint[0])
+	}
+}
+
+func TestNewEnvelope(t *testing.T) {
+	d, _ := ParseDSN("https://key@localhost/1")
+	ev := &Event{EventID: "abc", Platform: platform, Level: LevelError}
+
+	data, err := newEnvelope(ev, *d, time.Unix(0, 0).UTC())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines but received %d.\n", len(lines))
+	}
+
+	e := `{"dsn":"https://key@localhost/1","event_id":"abc","sent_at":"1970-01-01T00:00:00Z"}`
+	if string(lines[0]) != e {
+		t.Errorf("Expected %s but received %s.\n", e, lines[0])
+	}
+}
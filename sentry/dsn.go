@@ -0,0 +1,123 @@
+package sentry
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidDSN indicates that a DSN couldn't be parsed.
+var ErrInvalidDSN = errors.New("invalid DSN")
+
+// DSN types identify a Sentry project and the keys used to send it events.
+//
+// A DSN has the form {scheme}://{public key}[:{secret key}]@{host}[{path}]/{project}.
+type DSN struct {
+
+	// The scheme of the server's URL, either http or https.
+	Scheme string
+
+	// The project's public key.
+	PublicKey string
+
+	// The project's secret key, or an empty string if it doesn't have one.
+	SecretKey string
+
+	// The server's host and optional port.
+	Host string
+
+	// The path that the server is hosted at, without a trailing slash.
+	Path string
+
+	// The project's ID.
+	ProjectID string
+}
+
+// Initializers
+
+// ParseDSN parses and returns the DSN, s. If s isn't a valid DSN, then an error
+// that wraps ErrInvalidDSN is returned.
+func ParseDSN(s string) (*DSN, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDSN, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrInvalidDSN, u.Scheme)
+	}
+
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("%w: missing public key", ErrInvalidDSN)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: missing host", ErrInvalidDSN)
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	if i < 0 || path[i+1:] == "" {
+		return nil, fmt.Errorf("%w: missing project ID", ErrInvalidDSN)
+	}
+
+	secret, _ := u.User.Password()
+
+	return &DSN{
+		Scheme:    u.Scheme,
+		PublicKey: u.User.Username(),
+		SecretKey: secret,
+		Host:      u.Host,
+		Path:      path[:i],
+		ProjectID: path[i+1:],
+	}, nil
+}
+
+// Exported methods
+
+// EnvelopeURL returns the URL that envelopes are sent to.
+func (d DSN) EnvelopeURL() string {
+	return fmt.Sprintf(
+		"%s://%s%s/api/%s/envelope/",
+		d.Scheme,
+		d.Host,
+		d.Path,
+		d.ProjectID,
+	)
+}
+
+// AuthHeader returns the value of the X-Sentry-Auth header sent with requests
+// to the server.
+func (d DSN) AuthHeader() string {
+	h := fmt.Sprintf(
+		"Sentry sentry_version=%d, sentry_client=%s, sentry_key=%s",
+		protocolVersion,
+		clientName,
+		d.PublicKey,
+	)
+
+	if d.SecretKey != "" {
+		h += ", sentry_secret=" + d.SecretKey
+	}
+
+	return h
+}
+
+// Stringer interface methods
+
+func (d DSN) String() string {
+	user := d.PublicKey
+	if d.SecretKey != "" {
+		user += ":" + d.SecretKey
+	}
+
+	return fmt.Sprintf(
+		"%s://%s@%s%s/%s",
+		d.Scheme,
+		user,
+		d.Host,
+		d.Path,
+		d.ProjectID,
+	)
+}
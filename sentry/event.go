@@ -0,0 +1,319 @@
+// Package sentry exports errors created by package wrappederror as Sentry
+// events, and sends them to Sentry or any server that accepts Sentry
+// envelopes.
+package sentry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	we "github.com/colinc86/wrappederror"
+)
+
+// The platform of the events created by the package.
+const platform = "go"
+
+// Level types define the level of an event.
+type Level string
+
+// A group of event levels.
+const (
+	LevelDebug   Level = "debug"
+	LevelInfo    Level = "info"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+	LevelFatal   Level = "fatal"
+)
+
+// Event types describe an error as a Sentry event.
+type Event struct {
+
+	// The event's ID, a 32 character hexadecimal UUID.
+	EventID string `json:"event_id"`
+
+	// The time that the error was created.
+	Timestamp time.Time `json:"timestamp"`
+
+	// The platform that created the event.
+	Platform string `json:"platform"`
+
+	// The event's level.
+	Level Level `json:"level"`
+
+	// The release of the application that created the event.
+	Release string `json:"release,omitempty"`
+
+	// The environment that the application was running in.
+	Environment string `json:"environment,omitempty"`
+
+	// The name of the server that created the event.
+	ServerName string `json:"server_name,omitempty"`
+
+	// The errors in the error's chain.
+	Exception *ExceptionList `json:"exception,omitempty"`
+
+	// The event's tags.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// The values used to group the event with other events.
+	Fingerprint []string `json:"fingerprint,omitempty"`
+
+	// Additional values that describe the event.
+	Extra map[string]interface{} `json:"extra,omitempty"`
+}
+
+// ExceptionList types contain an event's exceptions.
+type ExceptionList struct {
+
+	// The exceptions ordered from the innermost error to the error that the
+	// event was created from.
+	Values []Exception `json:"values"`
+}
+
+// Exception types describe a single error in an error chain.
+type Exception struct {
+
+	// The error's type, or its kind or code if it has one.
+	Type string `json:"type"`
+
+	// The error's own message, without the messages of the errors that it
+	// wraps.
+	Value string `json:"value"`
+
+	// The stack of the goroutine that created the error, or nil if the error
+	// didn't capture its call information.
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Stacktrace types contain the frames of a stack.
+type Stacktrace struct {
+
+	// The stack's frames ordered from the oldest call to the newest call.
+	Frames []Frame `json:"frames"`
+}
+
+// Frame types describe a single stack frame.
+type Frame struct {
+
+	// The frame's function name without its package.
+	Function string `json:"function,omitempty"`
+
+	// The import path of the package that contains the frame's function.
+	Module string `json:"module,omitempty"`
+
+	// The name of the frame's source file.
+	Filename string `json:"filename,omitempty"`
+
+	// The full path of the frame's source file.
+	AbsPath string `json:"abs_path,omitempty"`
+
+	// The frame's line number.
+	Lineno int `json:"lineno,omitempty"`
+
+	// Whether or not the frame belongs to the application rather than the
+	// standard library or a dependency.
+	InApp bool `json:"in_app"`
+
+	// The source lines before the frame's line.
+	PreContext []string `json:"pre_context,omitempty"`
+
+	// The source of the frame's line.
+	ContextLine string `json:"context_line,omitempty"`
+
+	// The source lines after the frame's line.
+	PostContext []string `json:"post_context,omitempty"`
+}
+
+// Initializers
+
+// newEvent creates and returns a new event from the error, e, with the
+// client's options.
+func newEvent(e we.Error, o options) *Event {
+	ev := &Event{
+		EventID:     newEventID(),
+		Timestamp:   time.Now().UTC(),
+		Platform:    platform,
+		Level:       LevelError,
+		Release:     o.release,
+		Environment: o.environment,
+		ServerName:  o.serverName,
+		Tags:        make(map[string]string),
+		Extra:       make(map[string]interface{}),
+	}
+
+	for k, v := range o.tags {
+		ev.Tags[k] = v
+	}
+
+	chain := e.Chain()
+	values := make([]Exception, len(chain))
+	for i, err := range chain {
+		values[len(chain)-1-i] = newException(err, o)
+	}
+	ev.Exception = &ExceptionList{Values: values}
+
+	if code := e.Code(); code != "" {
+		ev.Tags["code"] = string(code)
+	}
+
+	if kind := e.Kind(); kind != "" {
+		ev.Tags["kind"] = string(kind)
+	}
+
+	if m := e.Metadata; m != nil {
+		ev.Timestamp = m.Time.UTC()
+		ev.Extra["index"] = m.Index
+		ev.Extra["similar"] = m.Similar
+		ev.Extra["duration"] = m.Duration.String()
+
+		if m.Sampled {
+			ev.Extra["sampled"] = true
+		}
+
+		if m.Fingerprint != "" {
+			ev.Fingerprint = []string{m.Fingerprint}
+		}
+
+		if s := m.Severity; s != nil {
+			ev.Level = severityLevel(s.Level)
+			ev.Tags["severity.level"] = string(s.Level)
+			ev.Tags["severity.title"] = s.Title
+		}
+	}
+
+	return ev
+}
+
+// newException creates and returns a new exception from the error, err.
+func newException(err error, o options) Exception {
+	var e *we.Error
+	switch t := err.(type) {
+	case we.Error:
+		e = &t
+	case *we.Error:
+		e = t
+	default:
+		return Exception{
+			Type:  fmt.Sprintf("%T", err),
+			Value: we.Redact(errorMessage(err)),
+		}
+	}
+
+	x := Exception{
+		Type:  fmt.Sprintf("%T", err),
+		Value: e.FormatString(string(we.ErrorFormatTokenContext)),
+	}
+
+	if kind := e.Kind(); kind != "" {
+		x.Type = string(kind)
+	} else if code := e.Code(); code != "" {
+		x.Type = string(code)
+	}
+
+	if e.Caller != nil {
+		x.Stacktrace = newStacktrace(*e.Caller, o)
+	}
+
+	return x
+}
+
+// newStacktrace creates and returns a new stacktrace from the caller, c.
+func newStacktrace(c we.Caller, o options) *Stacktrace {
+	wfs := c.Frames()
+	if len(wfs) == 0 {
+		return nil
+	}
+
+	frames := make([]Frame, len(wfs))
+	for i, wf := range wfs {
+		frames[len(wfs)-1-i] = newFrame(wf, o)
+	}
+
+	// The caller's own frame is the newest frame, and the only frame with a
+	// source fragment.
+	if f := c.Fragment(); f != nil {
+		addSourceContext(&frames[len(frames)-1], *f, c.Line)
+	}
+
+	return &Stacktrace{Frames: frames}
+}
+
+// newFrame creates and returns a new frame from the stack frame, f.
+func newFrame(f we.Frame, o options) Frame {
+	fn := f.Function
+	if f.Package != "" {
+		fn = strings.TrimPrefix(fn, f.Package+".")
+	}
+
+	filename := f.File
+	if i := strings.LastIndex(filename, "/"); i >= 0 {
+		filename = filename[i+1:]
+	}
+
+	return Frame{
+		Function: fn,
+		Module:   f.Package,
+		Filename: filename,
+		AbsPath:  f.File,
+		Lineno:   f.Line,
+		InApp:    o.inApp(f),
+	}
+}
+
+// Non-exported functions
+
+// newEventID returns a new random event ID.
+func newEventID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	// Set the UUID's version to 4 and its variant to RFC 4122.
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return hex.EncodeToString(b[:])
+}
+
+// addSourceContext adds the lines of the source fragment, f, around line to the
+// frame.
+func addSourceContext(frame *Frame, f we.SourceFragment, line int) {
+	if f.File != frame.AbsPath || line < f.LowerLine || line > f.UpperLine {
+		return
+	}
+
+	lines := strings.Split(strings.TrimSuffix(f.Source, "\n"), "\n")
+	i := line - f.LowerLine
+	if i >= len(lines) {
+		return
+	}
+
+	frame.PreContext = lines[:i]
+	frame.ContextLine = lines[i]
+	frame.PostContext = lines[i+1:]
+}
+
+// errorMessage returns the message of the error, err, without the message of
+// the error that it wraps, if it ends with it.
+func errorMessage(err error) string {
+	m := err.Error()
+	if inner := errors.Unwrap(err); inner != nil {
+		m = strings.TrimSuffix(m, ": "+inner.Error())
+	}
+	return m
+}
+
+// severityLevel returns the event level of the severity level, l.
+func severityLevel(l we.ErrorSeverityLevel) Level {
+	switch l {
+	case we.ErrorSeverityLevelLow:
+		return LevelWarning
+	case we.ErrorSeverityLevelSevere:
+		return LevelFatal
+	default:
+		return LevelError
+	}
+}
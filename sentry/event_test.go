@@ -0,0 +1,177 @@
+package sentry
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	we "github.com/colinc86/wrappederror"
+)
+
+func TestEvent(t *testing.T) {
+	c, err := NewClient(
+		"https://key@localhost/1",
+		WithRelease("1.0.0"),
+		WithEnvironment("test"),
+		WithTag("service", "api"),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	severity, _ := we.NewErrorSeverity("Timeout", "timeout", we.ErrorSeverityLevelSevere)
+	inner := fmt.Errorf("dial: %w", errors.New("i/o timeout"))
+	e := we.NewWithOptions(inner, "get failed", we.WithSeverity(severity), we.WithCode("UNAVAILABLE"))
+
+	ev := c.Event(*e)
+
+	if len(ev.EventID) != 32 {
+		t.Errorf("Expected a 32 character event ID but received %s.\n", ev.EventID)
+	}
+	if ev.Level != LevelFatal {
+		t.Errorf("Expected %s but received %s.\n", LevelFatal, ev.Level)
+	}
+	if ev.Release != "1.0.0" || ev.Environment != "test" {
+		t.Errorf("Unexpected release and environment: %s, %s\n", ev.Release, ev.Environment)
+	}
+	if !ev.Timestamp.Equal(e.Metadata.Time) {
+		t.Errorf("Expected %s but received %s.\n", e.Metadata.Time, ev.Timestamp)
+	}
+
+	for k, v := range map[string]string{
+		"service":        "api",
+		"code":           "UNAVAILABLE",
+		"severity.level": "severe",
+		"severity.title": "Timeout",
+	} {
+		if ev.Tags[k] != v {
+			t.Errorf("Expected tag %s to be %s but received %s.\n", k, v, ev.Tags[k])
+		}
+	}
+
+	if len(ev.Fingerprint) != 1 || ev.Fingerprint[0] != e.Metadata.Fingerprint {
+		t.Errorf("Expected fingerprint %s but received %v.\n", e.Metadata.Fingerprint, ev.Fingerprint)
+	}
+
+	if ev.Extra["index"] != e.Metadata.Index {
+		t.Errorf("Expected index %d but received %v.\n", e.Metadata.Index, ev.Extra["index"])
+	}
+
+	values := ev.Exception.Values
+	if len(values) != 3 {
+		t.Fatalf("Expected 3 exceptions but received %d.\n", len(values))
+	}
+	if values[0].Value != "i/o timeout" || values[1].Value != "dial" {
+		t.Errorf("Unexpected exceptions: %+v\n", values)
+	}
+	if values[2].Type != "UNAVAILABLE" || values[2].Value != "get failed" {
+		t.Errorf("Unexpected exception: %+v\n", values[2])
+	}
+	if values[0].Stacktrace != nil || values[1].Stacktrace != nil {
+		t.Error("Unexpected stack trace.")
+	}
+
+	st := values[2].Stacktrace
+	if st == nil || len(st.Frames) == 0 {
+		t.Fatal("Expected a stack trace.")
+	}
+
+	f := st.Frames[len(st.Frames)-1]
+	if f.Function != "TestEvent" || f.Module != "github.com/colinc86/wrappederror/sentry" {
+		t.Errorf("Unexpected frame: %+v\n", f)
+	}
+	if f.Filename != "event_test.go" || f.Lineno != e.Caller.Line || !f.InApp {
+		t.Errorf("Unexpected frame: %+v\n", f)
+	}
+	if !strings.Contains(f.ContextLine, "we.NewWithOptions(inner") {
+		t.Errorf("Unexpected context line: %s\n", f.ContextLine)
+	}
+	if len(f.PreContext) != 2 || len(f.PostContext) != 2 {
+		t.Errorf("Expected 2 lines of context but received %d and %d.\n", len(f.PreContext), len(f.PostContext))
+	}
+
+	if st.Frames[0].InApp {
+		t.Errorf("Expected frame %s not to be in-app.\n", st.Frames[0].Function)
+	}
+}
+
+func TestEventWithoutCaller(t *testing.T) {
+	c, _ := NewClient("https://key@localhost/1")
+	e := we.NewWithOptions(nil, "no caller", we.WithoutCaller())
+
+	ev := c.Event(*e)
+	if ev.Level != LevelError {
+		t.Errorf("Expected %s but received %s.\n", LevelError, ev.Level)
+	}
+
+	values := ev.Exception.Values
+	if len(values) != 1 || values[0].Stacktrace != nil {
+		t.Errorf("Unexpected exceptions: %+v\n", values)
+	}
+}
+
+func TestEventRedaction(t *testing.T) {
+	defer we.Config().SetRedactionPatterns()
+	we.Config().SetRedactionPatterns(regexp.MustCompile(`token=(\S+)`))
+
+	c, _ := NewClient("https://key@localhost/1")
+	e := we.New(errors.New("token=abc rejected"), "auth failed")
+
+	for _, x := range c.Event(*e).Exception.Values {
+		if strings.Contains(x.Value, "abc") {
+			t.Errorf("Expected %s to be redacted.\n", x.Value)
+		}
+	}
+}
+
+func TestSeverityLevel(t *testing.T) {
+	for l, e := range map[we.ErrorSeverityLevel]Level{
+		we.ErrorSeverityLevelNone:     LevelError,
+		we.ErrorSeverityLevelLow:      LevelWarning,
+		we.ErrorSeverityLevelModerate: LevelError,
+		we.ErrorSeverityLevelHigh:     LevelError,
+		we.ErrorSeverityLevelSevere:   LevelFatal,
+	} {
+		if r := severityLevel(l); r != e {
+			t.Errorf("Expected %s but received %s.\n", e, r)
+		}
+	}
+}
+
+func TestInApp(t *testing.T) {
+	o := options{}
+	t.Run("In app 0", func(t *testing.T) {
+		testInApp(t, o, we.Frame{Package: "main", File: "/src/app/main.go"}, true)
+	})
+	t.Run("In app 1", func(t *testing.T) {
+		testInApp(t, o, we.Frame{Package: "net/http", File: "/go/src/net/http/server.go"}, false)
+	})
+	t.Run("In app 2", func(t *testing.T) {
+		testInApp(t, o, we.Frame{Package: "example.com/app/db", File: "/src/app/db/db.go"}, true)
+	})
+	t.Run("In app 3", func(t *testing.T) {
+		testInApp(t, o, we.Frame{Package: "example.com/lib", File: "/go/pkg/mod/example.com/lib@v1/lib.go"}, false)
+	})
+	t.Run("In app 4", func(t *testing.T) {
+		testInApp(t, o, we.Frame{Package: "example.com/lib", File: "/src/app/vendor/example.com/lib/lib.go"}, false)
+	})
+
+	o = options{inAppPackages: []string{"example.com/app"}}
+	t.Run("In app 5", func(t *testing.T) {
+		testInApp(t, o, we.Frame{Package: "example.com/app/db"}, true)
+	})
+	t.Run("In app 6", func(t *testing.T) {
+		testInApp(t, o, we.Frame{Package: "example.com/apple"}, false)
+	})
+	t.Run("In app 7", func(t *testing.T) {
+		testInApp(t, o, we.Frame{Package: "main"}, false)
+	})
+}
+
+func testInApp(t *testing.T, o options, f we.Frame, e bool) {
+	if r := o.inApp(f); r != e {
+		t.Errorf("Expected %t but received %t.\n", e, r)
+	}
+}
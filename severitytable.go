@@ -58,7 +58,7 @@ func (t *severityTable) unregister(severity *ErrorSeverity) {
 // bestMatch returns the best match or errorSeverityUnknown if none was found
 // with a match greater than 0.0.
 //
-// It walks the entire error chain beginning with err and finds the best match
+// It walks the entire error tree beginning with err and finds the best match
 // error severity.
 func (t *severityTable) bestMatch(err error) *ErrorSeverity {
 	t.severitiesMutex.RLock()
@@ -67,8 +67,7 @@ func (t *severityTable) bestMatch(err error) *ErrorSeverity {
 	bestMatch := 0.0
 	var bestMatchErrorSeverity *ErrorSeverity
 
-	e := err
-	for e != nil {
+	for _, e := range errorTree(err) {
 		for _, s := range t.severities {
 			m := s.match(e)
 			if m > bestMatch {
@@ -76,9 +75,22 @@ func (t *severityTable) bestMatch(err error) *ErrorSeverity {
 				bestMatchErrorSeverity = s
 			}
 		}
-
-		e = errors.Unwrap(e)
 	}
 
 	return bestMatchErrorSeverity
 }
+
+// codeMatch returns the first error severity registered with the given code,
+// or nil if there isn't one.
+func (t *severityTable) codeMatch(code ErrorCode) *ErrorSeverity {
+	t.severitiesMutex.RLock()
+	defer t.severitiesMutex.RUnlock()
+
+	for _, s := range t.severities {
+		if s.Code == code {
+			return s
+		}
+	}
+
+	return nil
+}
@@ -70,4 +70,12 @@ func TestSeverityTableBestMatch(t *testing.T) {
 	if !es.equals(testErrorSeverities.es2) {
 		t.Errorf("Unexpected severity %s.\n", es)
 	}
+
+	es = st.bestMatch(newMultiError([]error{
+		errors.New("xyz"),
+		New(errors.New("abcde"), "xyz"),
+	}))
+	if !es.equals(testErrorSeverities.es2) {
+		t.Errorf("Unexpected severity %s.\n", es)
+	}
 }
@@ -0,0 +1,166 @@
+package wrappederror
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// Initializers
+
+// newSlogValue creates a new slog group value from the error, e, that contains
+// the given fields. The group's keys match the keys of the error's full JSON
+// object.
+func newSlogValue(e Error, fields JSONFieldSet) slog.Value {
+	attrs := []slog.Attr{
+		slog.Any("context", redactContext(e.context)),
+		slog.Any("chain", newSlogChain(e)),
+		slog.Int("depth", e.Depth()),
+	}
+
+	if f := e.fields(); len(f) > 0 {
+		attrs = append(attrs, slog.Attr{
+			Key:   "fields",
+			Value: newSlogFieldsValue(f),
+		})
+	}
+
+	if e.Caller != nil &&
+		fields&(JSONFieldCaller|JSONFieldStack|JSONFieldSource) != 0 {
+		attrs = append(attrs, slog.Attr{
+			Key:   "caller",
+			Value: newSlogCallerValue(*e.Caller, fields),
+		})
+	}
+
+	if e.Process != nil && fields.Has(JSONFieldProcess) {
+		attrs = append(attrs, slog.Attr{
+			Key:   "process",
+			Value: newSlogProcessValue(*e.Process, fields),
+		})
+	}
+
+	if e.Metadata != nil {
+		if fields.Has(JSONFieldMetadata) {
+			attrs = append(
+				attrs,
+				slog.Time("time", e.Metadata.Time),
+				slog.Duration("duration", e.Metadata.Duration),
+				slog.Int("index", e.Metadata.Index),
+				slog.Int("similar", e.Metadata.Similar),
+			)
+
+			if e.Metadata.Fingerprint != "" {
+				attrs = append(
+					attrs,
+					slog.String("fingerprint", e.Metadata.Fingerprint),
+				)
+			}
+
+			if e.Metadata.Sampled {
+				attrs = append(attrs, slog.Bool("sampled", true))
+			}
+		}
+
+		if fields.Has(JSONFieldCode) {
+			if e.Metadata.Code != "" {
+				attrs = append(attrs, slog.String("code", string(e.Metadata.Code)))
+			}
+			if e.Metadata.Kind != "" {
+				attrs = append(attrs, slog.String("kind", string(e.Metadata.Kind)))
+			}
+		}
+
+		if e.Metadata.Severity != nil && fields.Has(JSONFieldSeverity) {
+			attrs = append(attrs, slog.Group(
+				"severity",
+				slog.String("title", e.Metadata.Severity.Title),
+				slog.String("level", string(e.Metadata.Severity.Level)),
+			))
+		}
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// newSlogFieldsValue creates a new slog group value from the fields ordered by
+// their keys.
+func newSlogFieldsValue(fields map[string]interface{}) slog.Value {
+	r := redactFields(fields)
+	keys := make([]string, 0, len(r))
+	for k := range r {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, len(keys))
+	for i, k := range keys {
+		attrs[i] = slog.Any(k, r[k])
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// newSlogCallerValue creates a new slog group value from the caller, c, that
+// contains the given fields.
+func newSlogCallerValue(c Caller, fields JSONFieldSet) slog.Value {
+	var attrs []slog.Attr
+
+	if fields.Has(JSONFieldCaller) {
+		attrs = append(
+			attrs,
+			slog.String("file", c.File),
+			slog.String("function", c.Function),
+			slog.Int("line", c.Line),
+		)
+	}
+
+	if fields.Has(JSONFieldStack) {
+		attrs = append(attrs, slog.String("stackTrace", c.StackTrace()))
+	}
+
+	if fields.Has(JSONFieldSource) {
+		if f := c.Fragment(); f != nil {
+			attrs = append(attrs, slog.String("sourceFragment", f.Source))
+		}
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// newSlogProcessValue creates a new slog group value from the process, p, that
+// contains the given fields.
+func newSlogProcessValue(p Process, fields JSONFieldSet) slog.Value {
+	attrs := []slog.Attr{
+		slog.Int("goroutines", p.Routines),
+		slog.Int("cpus", p.CPUs),
+		slog.Int("cgos", p.CGO),
+	}
+
+	if p.Memory != nil && fields.Has(JSONFieldMemory) {
+		attrs = append(attrs, slog.Group(
+			"memory",
+			slog.Uint64("alloc", p.Memory.Alloc),
+			slog.Uint64("totalAlloc", p.Memory.TotalAlloc),
+			slog.Uint64("sys", p.Memory.Sys),
+			slog.Uint64("heapObjects", p.Memory.HeapObjects),
+			slog.Uint64("numGC", uint64(p.Memory.NumGC)),
+		))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// newSlogChain returns the messages of the errors in the error's chain. Errors
+// defined by this package contribute their context.
+func newSlogChain(e Error) []string {
+	var c []string
+	for _, err := range e.Chain() {
+		if we, ok := asError(err); ok {
+			c = append(c, redactString(fmt.Sprintf("%+v", redactContext(we.context))))
+		} else {
+			c = append(c, redactString(err.Error()))
+		}
+	}
+	return c
+}
@@ -0,0 +1,117 @@
+package wrappederror
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestErrorLogValue(t *testing.T) {
+	packageState.config.SetMarshalMinimalJSON(true)
+	defer packageState.config.SetMarshalMinimalJSON(configDefaultMarshalMinimalJSON)
+
+	e := New(errors.New("inner"), "outer")
+	v := e.LogValue()
+	if v.Kind() != slog.KindGroup {
+		t.Fatalf("Expected %s but received %s.\n", slog.KindGroup, v.Kind())
+	}
+
+	keys := testSlogKeys(v)
+	for _, k := range []string{"context", "chain", "depth", "caller", "index", "similar"} {
+		if !keys[k] {
+			t.Errorf("Expected key %s.\n", k)
+		}
+	}
+
+	if keys["process"] {
+		t.Error("Unexpected process key.")
+	}
+}
+
+func TestErrorLogValueWith(t *testing.T) {
+	s, _ := NewErrorSeverity("test severity", "log value", ErrorSeverityLevelHigh)
+	e := NewWithOptions(nil, "log value", WithSeverity(s))
+
+	t.Run("Error log value with 0", func(t *testing.T) {
+		testErrorLogValueWith(
+			t,
+			e,
+			JSONFieldSetNone,
+			[]string{"context", "chain", "depth"},
+			[]string{"caller", "process", "index", "severity"},
+		)
+	})
+
+	t.Run("Error log value with 1", func(t *testing.T) {
+		testErrorLogValueWith(
+			t,
+			e,
+			JSONFieldProcess|JSONFieldSeverity,
+			[]string{"context", "process", "severity"},
+			[]string{"caller", "index"},
+		)
+	})
+
+	t.Run("Error log value with 2", func(t *testing.T) {
+		testErrorLogValueWith(
+			t,
+			e,
+			JSONFieldSetFull,
+			[]string{"caller", "process", "time", "index", "similar", "severity"},
+			nil,
+		)
+	})
+
+	c := NewWithOptions(nil, "log value", WithCode("E1"), WithKind("network"))
+	t.Run("Error log value with 3", func(t *testing.T) {
+		testErrorLogValueWith(
+			t,
+			c,
+			JSONFieldCode,
+			[]string{"code", "kind"},
+			[]string{"caller", "index"},
+		)
+	})
+}
+
+func testErrorLogValueWith(
+	t *testing.T,
+	e *Error,
+	fields JSONFieldSet,
+	present []string,
+	absent []string,
+) {
+	keys := testSlogKeys(e.LogValueWith(fields))
+	for _, k := range present {
+		if !keys[k] {
+			t.Errorf("Expected key %s.\n", k)
+		}
+	}
+	for _, k := range absent {
+		if keys[k] {
+			t.Errorf("Unexpected key %s.\n", k)
+		}
+	}
+}
+
+func TestNewSlogChain(t *testing.T) {
+	e := New(New(errors.New("c"), "b"), "a")
+	c := newSlogChain(*e)
+	if len(c) != 3 {
+		t.Fatalf("Expected %d but received %d.\n", 3, len(c))
+	}
+
+	for i, s := range []string{"a", "b", "c"} {
+		if c[i] != s {
+			t.Errorf("Expected %s but received %s.\n", s, c[i])
+		}
+	}
+}
+
+func testSlogKeys(v slog.Value) map[string]bool {
+	keys := make(map[string]bool)
+	for _, a := range v.Group() {
+		keys[a.Key] = true
+	}
+	return keys
+}
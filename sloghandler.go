@@ -0,0 +1,130 @@
+package wrappederror
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler types are slog handlers that expand errors created by this
+// package in to groups of attributes before passing records to another
+// handler.
+//
+// Attributes with Error or *Error values, including attributes nested in
+// groups and attributes added with WithAttrs, are replaced with the value
+// returned by the error's LogValueWith method.
+type SlogHandler struct {
+
+	// The handler that receives expanded records.
+	next slog.Handler
+
+	// The fields to include in expanded errors.
+	fields JSONFieldSet
+
+	// Whether or not fields overrides the package's configuration.
+	hasFields bool
+}
+
+// Initializers
+
+// NewSlogHandler creates and returns a new slog handler that expands errors
+// and passes records to next.
+//
+// Errors are expanded with the fields that are included when they are marshaled
+// in to JSON.
+func NewSlogHandler(next slog.Handler) *SlogHandler {
+	return &SlogHandler{next: next}
+}
+
+// NewSlogHandlerWith creates and returns a new slog handler that expands errors
+// with the given fields and passes records to next, regardless of the
+// package's JSON configuration.
+func NewSlogHandlerWith(next slog.Handler, fields JSONFieldSet) *SlogHandler {
+	return &SlogHandler{
+		next:      next,
+		fields:    fields,
+		hasFields: true,
+	}
+}
+
+// slog Handler interface methods
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle expands the record's errors and passes it to the next handler.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	fields := h.fieldSet()
+
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(expandSlogAttr(a, fields))
+		return true
+	})
+
+	return h.next.Handle(ctx, nr)
+}
+
+// WithAttrs returns a new handler whose next handler has the given attributes
+// with their errors expanded.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := h.fieldSet()
+	expanded := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		expanded[i] = expandSlogAttr(a, fields)
+	}
+
+	return &SlogHandler{
+		next:      h.next.WithAttrs(expanded),
+		fields:    h.fields,
+		hasFields: h.hasFields,
+	}
+}
+
+// WithGroup returns a new handler whose next handler has the given group.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return &SlogHandler{
+		next:      h.next.WithGroup(name),
+		fields:    h.fields,
+		hasFields: h.hasFields,
+	}
+}
+
+// Non-exported methods
+
+// fieldSet returns the fields to include in expanded errors.
+func (h *SlogHandler) fieldSet() JSONFieldSet {
+	if h.hasFields {
+		return h.fields
+	}
+	return packageState.config.logFieldSet()
+}
+
+// Non-exported functions
+
+// expandSlogAttr replaces the attribute's value with the given fields of its
+// error if the value is an Error or *Error. Groups are expanded recursively.
+func expandSlogAttr(a slog.Attr, fields JSONFieldSet) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			expanded[i] = expandSlogAttr(ga, fields)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(expanded...)}
+
+	case slog.KindAny, slog.KindLogValuer:
+		switch e := a.Value.Any().(type) {
+		case Error:
+			return slog.Attr{Key: a.Key, Value: e.LogValueWith(fields)}
+		case *Error:
+			if e != nil {
+				return slog.Attr{Key: a.Key, Value: e.LogValueWith(fields)}
+			}
+		}
+	}
+
+	return a
+}
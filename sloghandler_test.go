@@ -0,0 +1,91 @@
+package wrappederror
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogHandlerHandle(t *testing.T) {
+	var b bytes.Buffer
+	l := slog.New(NewSlogHandlerWith(
+		slog.NewJSONHandler(&b, nil),
+		JSONFieldCaller,
+	))
+
+	e := New(errors.New("inner"), "outer")
+	l.Error("failed", "err", e, slog.Group("g", "nested", *e))
+
+	m := testSlogHandlerRecord(t, &b)
+	testSlogHandlerError(t, m["err"], true)
+
+	g, ok := m["g"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected group.")
+	}
+	testSlogHandlerError(t, g["nested"], true)
+}
+
+func TestSlogHandlerWithAttrs(t *testing.T) {
+	var b bytes.Buffer
+	h := NewSlogHandlerWith(slog.NewJSONHandler(&b, nil), JSONFieldSetNone)
+	l := slog.New(h.WithAttrs([]slog.Attr{slog.Any("err", New(nil, "attr"))}))
+	l.Info("message")
+
+	m := testSlogHandlerRecord(t, &b)
+	testSlogHandlerError(t, m["err"], false)
+}
+
+func TestSlogHandlerWithGroup(t *testing.T) {
+	var b bytes.Buffer
+	h := NewSlogHandlerWith(slog.NewJSONHandler(&b, nil), JSONFieldCaller)
+	l := slog.New(h.WithGroup("request"))
+	l.Info("message", "err", New(nil, "group"))
+
+	m := testSlogHandlerRecord(t, &b)
+	r, ok := m["request"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected group.")
+	}
+	testSlogHandlerError(t, r["err"], true)
+}
+
+func TestSlogHandlerForeignError(t *testing.T) {
+	var b bytes.Buffer
+	l := slog.New(NewSlogHandler(slog.NewJSONHandler(&b, nil)))
+	l.Info("message", "err", errors.New("foreign"))
+
+	m := testSlogHandlerRecord(t, &b)
+	if s, ok := m["err"].(string); !ok || s != "foreign" {
+		t.Errorf("Expected %s but received %v.\n", "foreign", m["err"])
+	}
+}
+
+func testSlogHandlerRecord(t *testing.T, b *bytes.Buffer) map[string]interface{} {
+	var m map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &m); err != nil {
+		t.Fatalf("Error unmarshaling record: %s\n", err)
+	}
+	return m
+}
+
+func testSlogHandlerError(t *testing.T, v interface{}, caller bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected error group but received %v.\n", v)
+	}
+
+	if _, ok := m["context"]; !ok {
+		t.Error("Expected context.")
+	}
+
+	if _, ok := m["caller"]; ok != caller {
+		t.Errorf("Expected caller %t but received %t.\n", caller, ok)
+	}
+
+	if _, ok := m["process"]; ok {
+		t.Error("Unexpected process.")
+	}
+}
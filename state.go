@@ -1,6 +1,7 @@
 package wrappederror
 
 import (
+	"context"
 	"time"
 )
 
@@ -8,6 +9,10 @@ import (
 type state struct {
 	errorMap          *errorMap
 	serverityTable    *severityTable
+	helperTable       *helperTable
+	encoderTable      *encoderTable
+	reporterTable     *reporterTable
+	observerTable     *observerTable
 	processLaunchTime *safeValue
 	config            *Configuration
 }
@@ -27,20 +32,31 @@ func newState() *state {
 func (s *state) reset() {
 	s.errorMap = newErrorMap()
 	s.serverityTable = newSeverityTable()
+	s.helperTable = newHelperTable()
+	s.encoderTable = newEncoderTable()
+	if s.reporterTable != nil {
+		s.reporterTable.close()
+	}
+	s.reporterTable = newReporterTable()
+	s.observerTable = newObserverTable()
 	s.processLaunchTime = newSafeValue(time.Now())
 	s.config = newConfiguration()
 }
 
-// getSimilarErrorCount gets and returns the number of errors in the error hash
-// map equal to err.
-func (s state) getSimilarErrorCount(err error) int {
-	if !s.config.TrackSimilarErrors() || err == nil {
+// getSimilarErrorCount gets and returns the number of errors in the error map
+// with the fingerprint, fp, and adds another error with the fingerprint.
+func (s state) getSimilarErrorCount(fp string) int {
+	if !s.config.TrackSimilarErrors() || fp == "" {
 		return 0
 	}
 
-	st := s.errorMap.similarErrors(err)
-	s.errorMap.addError(err)
-	return st
+	return s.errorMap.addError(fp, s.config.similarErrorPolicy())
+}
+
+// getSimilarErrorStats gets and returns the statistics of the n fingerprints
+// with the most similar errors.
+func (s state) getSimilarErrorStats(n int) []SimilarErrorStat {
+	return s.errorMap.stats(n, s.config.similarErrorPolicy())
 }
 
 // registerSeverity registers the severity with the state's severity table.
@@ -58,6 +74,90 @@ func (s state) getBestMatchSeverity(err error) *ErrorSeverity {
 	return s.serverityTable.bestMatch(err)
 }
 
+// getCodeSeverity gets the severity registered with the given code.
+func (s state) getCodeSeverity(code ErrorCode) *ErrorSeverity {
+	return s.serverityTable.codeMatch(code)
+}
+
+// registerEncoder registers the encoder with the state's encoder table.
+func (s state) registerEncoder(name string, encoder Encoder) error {
+	return s.encoderTable.register(name, encoder)
+}
+
+// unregisterEncoder unregisters the encoder from the state's encoder table.
+func (s state) unregisterEncoder(name string) {
+	s.encoderTable.unregister(name)
+}
+
+// getEncoder gets the encoder with the given name.
+func (s state) getEncoder(name string) Encoder {
+	return s.encoderTable.encoder(name)
+}
+
+// registerReporter registers the reporter with the state's reporter table.
+func (s state) registerReporter(
+	name string,
+	reporter Reporter,
+	min ErrorSeverityLevel,
+) error {
+	return s.reporterTable.register(
+		name,
+		reporter,
+		min,
+		s.config.ReporterQueueSize(),
+	)
+}
+
+// unregisterReporter unregisters the reporter from the state's reporter table.
+func (s state) unregisterReporter(name string) {
+	s.reporterTable.unregister(name)
+}
+
+// report queues the error for the reporters in the state's reporter table.
+func (s state) report(e Error) {
+	s.reporterTable.report(e)
+}
+
+// flushReporters waits for the errors queued for the state's reporters to be
+// reported.
+func (s state) flushReporters(ctx context.Context) error {
+	return s.reporterTable.flush(ctx)
+}
+
+// getReporterStats gets the statistics of the reporter with the given name.
+func (s state) getReporterStats(name string) (ReporterStat, bool) {
+	return s.reporterTable.stats(name)
+}
+
+// registerObserver registers the observer with the state's observer table.
+func (s state) registerObserver(name string, observer Observer) error {
+	return s.observerTable.register(name, observer)
+}
+
+// unregisterObserver unregisters the observer from the state's observer table.
+func (s state) unregisterObserver(name string) {
+	s.observerTable.unregister(name)
+}
+
+// observe calls the observers in the state's observer table with the error.
+func (s state) observe(e Error) {
+	s.observerTable.observe(e)
+}
+
+// registerHelper marks the fully qualified function name, fn, as a helper.
+func (s state) registerHelper(fn string) {
+	s.helperTable.register(fn)
+}
+
+// isHelper returns whether or not the fully qualified function name, fn, has
+// been marked as a helper.
+func (s state) isHelper(fn string) bool {
+	if s.helperTable.isEmpty() {
+		return false
+	}
+	return s.helperTable.isHelper(fn)
+}
+
 // getDurationSinceLaunch gets the current duration since the process was
 // launched.
 func (s state) getDurationSinceLaunch() time.Duration {
@@ -0,0 +1,125 @@
+package wrappederror
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TextEncoder types encode errors as human-readable, indented blocks of text
+// similar to YAML.
+//
+// Wrapped errors are nested beneath their wrapping error's "wraps" key, and
+// multi-line values such as stack traces are written as indented blocks.
+type TextEncoder struct{}
+
+// Encode encodes the error, e, and writes the result to w.
+func (enc TextEncoder) Encode(w io.Writer, e Error) error {
+	n, err := newErrorEncoderNode(e)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	enc.writeFields(&b, n.fields, "", "")
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// Non-exported methods
+
+// writeFields writes the fields at the given indent. The first field is
+// prefixed with first instead of indent.
+func (enc TextEncoder) writeFields(
+	b *strings.Builder,
+	fields []encoderField,
+	indent string,
+	first string,
+) {
+	for i, f := range fields {
+		p := indent
+		if i == 0 {
+			p = first
+		}
+		enc.writeField(b, f.key, f.node, p, indent)
+	}
+}
+
+// writeField writes the field with the given key and node.
+func (enc TextEncoder) writeField(
+	b *strings.Builder,
+	key string,
+	n *encoderNode,
+	prefix string,
+	indent string,
+) {
+	switch n.kind {
+	case encoderNodeKindObject:
+		if len(n.fields) == 0 {
+			fmt.Fprintf(b, "%s%s: {}\n", prefix, key)
+			return
+		}
+		fmt.Fprintf(b, "%s%s:\n", prefix, key)
+		enc.writeFields(b, n.fields, indent+"  ", indent+"  ")
+	case encoderNodeKindArray:
+		if len(n.items) == 0 {
+			fmt.Fprintf(b, "%s%s: []\n", prefix, key)
+			return
+		}
+		fmt.Fprintf(b, "%s%s:\n", prefix, key)
+		for _, item := range n.items {
+			enc.writeItem(b, item, indent+"  ")
+		}
+	default:
+		enc.writeScalar(b, prefix+key+":", n, indent+"  ")
+	}
+}
+
+// writeItem writes an array item at the given indent.
+func (enc TextEncoder) writeItem(
+	b *strings.Builder,
+	n *encoderNode,
+	indent string,
+) {
+	switch n.kind {
+	case encoderNodeKindObject:
+		if len(n.fields) == 0 {
+			fmt.Fprintf(b, "%s- {}\n", indent)
+			return
+		}
+		enc.writeFields(b, n.fields, indent+"  ", indent+"- ")
+	case encoderNodeKindArray:
+		fmt.Fprintf(b, "%s-\n", indent)
+		for _, item := range n.items {
+			enc.writeItem(b, item, indent+"  ")
+		}
+	default:
+		enc.writeScalar(b, indent+"-", n, indent+"  ")
+	}
+}
+
+// writeScalar writes a scalar value after the prefix. Multi-line strings are
+// written as blocks at the given indent.
+func (enc TextEncoder) writeScalar(
+	b *strings.Builder,
+	prefix string,
+	n *encoderNode,
+	indent string,
+) {
+	s := n.scalarString()
+	if n.kind == encoderNodeKindString {
+		if strings.Contains(s, "\n") {
+			fmt.Fprintf(b, "%s |\n", prefix)
+			for _, l := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+				fmt.Fprintf(b, "%s%s\n", indent, l)
+			}
+			return
+		}
+
+		if s == "" {
+			s = `""`
+		}
+	}
+
+	fmt.Fprintf(b, "%s %s\n", prefix, s)
+}
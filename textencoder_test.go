@@ -0,0 +1,57 @@
+package wrappederror
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTextEncoder(t *testing.T) {
+	defer Config().SetMarshalMinimalJSON(configDefaultMarshalMinimalJSON)
+	defer Config().SetJSONFieldSet(configDefaultJSONFieldSet)
+	Config().SetJSONFieldSet(JSONFieldSetFull)
+
+	e := testEncoderError()
+
+	var b bytes.Buffer
+	if err := (TextEncoder{}).Encode(&b, *e); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	s := b.String()
+	ex := []string{
+		"caller:\n  file: encoder_test.go\n",
+		"  frames:\n    - function: " + e.Caller.Function + "\n",
+		"  stackTrace: |\n    " + e.Caller.Function + "\n",
+		fmt.Sprintf("metadata:\n  time: %s", e.Metadata.Time.Format("2006-01-02")),
+		"context: error 2\ndepth: 2\nwraps:\n",
+		"  context: error 1\n",
+		"  wraps:\n    error: root cause\n",
+	}
+	for _, x := range ex {
+		if !strings.Contains(s, x) {
+			t.Errorf("Expected \"%s\" in:\n%s\n", x, s)
+		}
+	}
+}
+
+func TestTextEncoderValues(t *testing.T) {
+	n, err := newEncoderNode(map[string]interface{}{
+		"a": []interface{}{},
+		"b": map[string]interface{}{},
+		"c": "",
+		"d": []interface{}{1, []interface{}{2}, map[string]interface{}{}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	var b strings.Builder
+	(TextEncoder{}).writeFields(&b, n.fields, "", "")
+
+	ex := "a: []\nb: {}\nc: \"\"\nd:\n  - 1\n  -\n    - 2\n  - {}\n"
+	if b.String() != ex {
+		t.Errorf("Expected:\n%s\nbut received:\n%s\n", ex, b.String())
+	}
+}
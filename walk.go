@@ -0,0 +1,145 @@
+package wrappederror
+
+// WalkAction types tell WalkTree how to continue after visiting an error.
+type WalkAction int
+
+// A group of walk actions.
+const (
+	// WalkActionContinue continues the walk with the errors that the visited
+	// error wraps.
+	WalkActionContinue WalkAction = iota
+
+	// WalkActionSkipChildren continues the walk, but skips the errors that the
+	// visited error wraps.
+	WalkActionSkipChildren
+
+	// WalkActionStop stops the walk.
+	WalkActionStop
+)
+
+// WalkNode types describe an error's position in an error tree.
+type WalkNode struct {
+
+	// The visited error.
+	Err error
+
+	// The number of errors between the root of the tree and the visited error.
+	//
+	// The root has level 0. For errors in the longest branch of the tree, this
+	// is the same index used by ErrorWithIndex.
+	Level int
+
+	// The number of errors in the longest branch beneath the visited error.
+	//
+	// Errors that don't wrap other errors have height 0. This is the same value
+	// returned by an error's Depth method.
+	Height int
+
+	// The error that wraps the visited error, or nil if the visited error is the
+	// root of the tree.
+	Parent error
+
+	// The position of each error from the root of the tree to the visited error
+	// amongst its siblings. The root's path is empty.
+	//
+	// For example, the path [0, 2] describes the third error wrapped by the first
+	// error wrapped by the root.
+	Path []int
+
+	// Whether or not the visited error is an Error or *Error type.
+	Wrapped bool
+}
+
+// walkTreeNode types are the nodes of an error tree with precomputed heights.
+type walkTreeNode struct {
+	err      error
+	height   int
+	children []*walkTreeNode
+}
+
+// Initializers
+
+// newWalkTreeNode creates and returns the tree of err, computing the height of
+// each node after its children.
+func newWalkTreeNode(err error) *walkTreeNode {
+	n := &walkTreeNode{err: err}
+	for _, c := range errorChildren(err) {
+		cn := newWalkTreeNode(c)
+		if cn.height+1 > n.height {
+			n.height = cn.height + 1
+		}
+		n.children = append(n.children, cn)
+	}
+	return n
+}
+
+// Exported methods
+
+// WalkTree calls the step function for each error in the error tree, including
+// the receiver, in pre-order.
+//
+// The action returned by the step function determines whether the walk
+// continues with the visited error's children, skips them, or stops.
+func (e Error) WalkTree(step func(node WalkNode) WalkAction) {
+	newWalkTreeNode(e).walk(nil, nil, 0, step)
+}
+
+// Non-exported methods
+
+// walk calls the step function for the node's error and the errors in its
+// tree. It returns false if the walk was stopped.
+func (n *walkTreeNode) walk(
+	parent error,
+	path []int,
+	level int,
+	step func(node WalkNode) WalkAction,
+) bool {
+	_, wrapped := asError(n.err)
+	action := step(WalkNode{
+		Err:     n.err,
+		Level:   level,
+		Height:  n.height,
+		Parent:  parent,
+		Path:    path,
+		Wrapped: wrapped,
+	})
+
+	switch action {
+	case WalkActionStop:
+		return false
+	case WalkActionSkipChildren:
+		return true
+	}
+
+	for i, c := range n.children {
+		cp := make([]int, len(path)+1)
+		copy(cp, path)
+		cp[len(path)] = i
+
+		if !c.walk(n.err, cp, level+1, step) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// branch returns the errors in the node's longest branch, starting with the
+// node's error. If more than one branch is the longest, then the first is
+// returned.
+func (n *walkTreeNode) branch() []error {
+	b := make([]error, 0, n.height+1)
+	for n != nil {
+		b = append(b, n.err)
+
+		var next *walkTreeNode
+		for _, c := range n.children {
+			if c.height == n.height-1 {
+				next = c
+				break
+			}
+		}
+		n = next
+	}
+	return b
+}
@@ -0,0 +1,133 @@
+package wrappederror
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestErrorWalkTree(t *testing.T) {
+	a := errors.New("a")
+	b := New(errors.New("c"), "b")
+	e := Join([]error{a, b}, "root")
+
+	var nodes []WalkNode
+	e.WalkTree(func(node WalkNode) WalkAction {
+		nodes = append(nodes, node)
+		return WalkActionContinue
+	})
+
+	if len(nodes) != 4 {
+		t.Fatalf("Expected %d but received %d.\n", 4, len(nodes))
+	}
+
+	t.Run("Error walk tree 0", func(t *testing.T) {
+		testErrorWalkTreeNode(t, nodes[0], 0, 2, nil, true)
+	})
+	t.Run("Error walk tree 1", func(t *testing.T) {
+		testErrorWalkTreeNode(t, nodes[1], 1, 0, []int{0}, false)
+	})
+	t.Run("Error walk tree 2", func(t *testing.T) {
+		testErrorWalkTreeNode(t, nodes[2], 1, 1, []int{1}, true)
+	})
+	t.Run("Error walk tree 3", func(t *testing.T) {
+		testErrorWalkTreeNode(t, nodes[3], 2, 0, []int{1, 0}, false)
+	})
+
+	if nodes[0].Parent != nil {
+		t.Error("Expected nil parent.")
+	}
+
+	if nodes[1].Err != a || nodes[2].Err != b || nodes[3].Parent != b {
+		t.Error("Unexpected errors.")
+	}
+}
+
+func testErrorWalkTreeNode(
+	t *testing.T,
+	node WalkNode,
+	level int,
+	height int,
+	path []int,
+	wrapped bool,
+) {
+	if node.Level != level {
+		t.Errorf("Expected level %d but received %d.\n", level, node.Level)
+	}
+
+	if node.Height != height {
+		t.Errorf("Expected height %d but received %d.\n", height, node.Height)
+	}
+
+	if node.Wrapped != wrapped {
+		t.Errorf("Expected wrapped %t but received %t.\n", wrapped, node.Wrapped)
+	}
+
+	if len(node.Path) != len(path) {
+		t.Fatalf("Expected path %v but received %v.\n", path, node.Path)
+	}
+
+	for i := range path {
+		if node.Path[i] != path[i] {
+			t.Errorf("Expected path %v but received %v.\n", path, node.Path)
+		}
+	}
+}
+
+func TestErrorWalkTreeActions(t *testing.T) {
+	e := Join([]error{
+		New(errors.New("a0"), "a"),
+		New(errors.New("b0"), "b"),
+	}, "root")
+
+	t.Run("Error walk tree actions 0", func(t *testing.T) {
+		testErrorWalkTreeAction(t, e, "a", WalkActionContinue, 5)
+	})
+	t.Run("Error walk tree actions 1", func(t *testing.T) {
+		testErrorWalkTreeAction(t, e, "a", WalkActionSkipChildren, 4)
+	})
+	t.Run("Error walk tree actions 2", func(t *testing.T) {
+		testErrorWalkTreeAction(t, e, "a", WalkActionStop, 2)
+	})
+}
+
+func testErrorWalkTreeAction(
+	t *testing.T,
+	e *Error,
+	ctx string,
+	action WalkAction,
+	ex int,
+) {
+	n := 0
+	e.WalkTree(func(node WalkNode) WalkAction {
+		n++
+		if we, ok := node.Err.(*Error); ok && we.Context() == ctx {
+			return action
+		}
+		return WalkActionContinue
+	})
+
+	if n != ex {
+		t.Errorf("Expected %d but received %d.\n", ex, n)
+	}
+}
+
+func TestErrorWalkTreeDeepest(t *testing.T) {
+	shallow := &os.PathError{Op: "open", Path: "a", Err: os.ErrNotExist}
+	deep := &os.PathError{Op: "open", Path: "b", Err: os.ErrNotExist}
+	e := Join([]error{shallow, New(New(deep, "inner"), "outer")}, "root")
+
+	var deepest *os.PathError
+	level := -1
+	e.WalkTree(func(node WalkNode) WalkAction {
+		if pe, ok := node.Err.(*os.PathError); ok && node.Level > level {
+			deepest = pe
+			level = node.Level
+		}
+		return WalkActionContinue
+	})
+
+	if deepest != deep {
+		t.Errorf("Expected %v but received %v.\n", deep, deepest)
+	}
+}